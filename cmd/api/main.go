@@ -4,6 +4,9 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	_ "github.com/fernandodr19/mybank-acc/docs/swagger"
@@ -12,9 +15,14 @@ import (
 	app "github.com/fernandodr19/mybank-acc/pkg"
 	"github.com/fernandodr19/mybank-acc/pkg/config"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/api"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/db/postgres"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/events"
 	grpc_acc "github.com/fernandodr19/mybank-acc/pkg/gateway/grpc"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/jobs"
 	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/tracing"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 )
@@ -22,16 +30,21 @@ import (
 // @title Swagger Mybank API
 // @version 1.0
 // @host localhost:3001
-// @basePath /api/v1
+// @basePath /
 // @schemes http
 // @license.name MIT
 // @license.url https://opensource.org/licenses/MIT
 // @description Documentation Mybank API
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	log := logger.Default()
 	log.Infoln("=== My Bank ACC ===")
 
-	ctx := context.Background()
+	// Cancelled on SIGINT/SIGTERM so servers can be gracefully stopped
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	// Load config
 	cfg, err := config.Load()
@@ -39,6 +52,12 @@ func main() {
 		log.WithError(err).Fatal("failed loading config")
 	}
 
+	// Setup tracing
+	flushTraces, err := tracing.Setup(ctx, cfg.Tracing, cfg.AppName)
+	if err != nil {
+		log.WithError(err).Fatal("failed setting up tracing")
+	}
+
 	// Setup postgres
 	dbConn, err := postgres.NewConnection(ctx, cfg.Postgres)
 	if err != nil {
@@ -46,42 +65,187 @@ func main() {
 	}
 
 	// Build app
-	app := app.BuildApp(dbConn)
+	app := app.BuildApp(dbConn, cfg.Holds)
+
+	// Setup outbox relay
+	publisher, err := events.NewPublisher(cfg.Outbox)
+	if err != nil {
+		log.WithError(err).Fatal("failed setting up events publisher")
+	}
+	relay := events.NewRelay(postgres.NewOutboxRepository(dbConn), publisher, cfg.Outbox)
+
+	// Setup holds sweeper
+	sweeper := jobs.NewHoldsSweeper(app.Accounts, cfg.Holds)
+
+	// Setup access token verification
+	verifier, err := auth.NewVerifier(cfg.Auth)
+	if err != nil {
+		log.WithError(err).Fatal("failed setting up auth")
+	}
 
 	// Build gRPC handler
-	grpcHandler := grpc_acc.BuildHandler(app)
+	grpcHandler, err := grpc_acc.BuildHandler(app, verifier, cfg.GRPC)
+	if err != nil {
+		log.WithError(err).Fatal("failed setting up gRPC")
+	}
 
 	// Build API handler
-	apiHandler := api.BuildHandler(app)
+	apiHandler := api.BuildHandler(app, verifier)
 
 	// Server up application
-	serveApp(apiHandler, grpcHandler, cfg, log)
+	serveApp(ctx, components{
+		API:         apiHandler,
+		GRPC:        grpcHandler,
+		Workers:     []worker{relay, sweeper},
+		Broker:      app.Broker,
+		DB:          dbConn,
+		FlushTraces: flushTraces,
+	}, cfg, log)
+}
+
+// components are the application parts served until shutdown
+type components struct {
+	API         http.Handler
+	GRPC        *grpc.Server
+	Workers     []worker // run in background until shutdown
+	Broker      *events.Broker
+	DB          *pgxpool.Pool
+	FlushTraces func(context.Context) error
+}
+
+// worker is a background job running until its context is done
+type worker interface {
+	Run(ctx context.Context)
 }
 
-func serveApp(apiHandler http.Handler, grpcHandler *grpc.Server, cfg *config.Config, log *logrus.Entry) {
+func serveApp(ctx context.Context, app components, cfg *config.Config, log *logrus.Entry) {
+	errs := make(chan error, 2)
+
 	// gRPC server
-	go func() {
-		protocol, address := cfg.GRPC.Protocol, cfg.GRPC.Address()
-		l, err := net.Listen(protocol, address)
-		if err != nil {
-			log.WithFields(logrus.Fields{
-				"protocol": protocol,
-				"address":  address,
-			}).WithError(err).Fatalln("failed to listen gRPC")
-		}
+	protocol, address := cfg.GRPC.Protocol, cfg.GRPC.Address()
+	l, err := net.Listen(protocol, address)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"protocol": protocol,
+			"address":  address,
+		}).WithError(err).Fatalln("failed to listen gRPC")
+	}
 
+	go func() {
 		log.WithField("address", address).Infoln("gRPC server starting...")
-		log.Fatal(grpcHandler.Serve(l))
+		errs <- app.GRPC.Serve(l)
 	}()
 
 	// REST server
 	server := &http.Server{
-		Handler:      apiHandler,
+		Handler:      app.API,
 		Addr:         cfg.API.Address(),
 		WriteTimeout: 15 * time.Second,
 		ReadTimeout:  15 * time.Second,
 	}
 
-	log.WithField("address", cfg.API.Address()).Info("rest server starting...")
-	log.Fatal(server.ListenAndServe())
+	go func() {
+		log.WithField("address", cfg.API.Address()).Info("rest server starting...")
+		errs <- server.ListenAndServe()
+	}()
+
+	// Background workers
+	workersCtx, stopWorkers := context.WithCancel(ctx)
+	workersDone := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		wg.Add(len(app.Workers))
+		for _, w := range app.Workers {
+			go func(w worker) {
+				defer wg.Done()
+				w.Run(workersCtx)
+			}(w)
+		}
+		wg.Wait()
+		close(workersDone)
+	}()
+
+	// Wait for a termination signal or for a server to fail
+	select {
+	case <-ctx.Done():
+		log.Infoln("shutdown signal received")
+	case err = <-errs:
+		log.WithError(err).Errorln("server stopped unexpectedly")
+	}
+
+	stopWorkers()
+
+	// watch streams never end by themselves, they would hold gRPC graceful stop until timeout
+	app.Broker.Close()
+
+	shutdown(server, app, workersDone, cfg.API.ShutdownTimeout, log)
+
+	if err != nil {
+		log.Fatalln("application stopped due to server failure")
+	}
+	log.Infoln("application gracefully stopped")
+}
+
+// shutdown stops accepting new requests, waits for in-flight ones and for background workers to stop,
+// releases DB connections and flushes pending spans.
+// Whatever is still running once timeout is reached gets forcefully interrupted.
+func shutdown(server *http.Server, app components, workersDone <-chan struct{}, timeout time.Duration, log *logrus.Entry) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		err := server.Shutdown(ctx)
+		if err != nil {
+			log.WithError(err).Errorln("failed draining rest server")
+			server.Close()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		stopped := make(chan struct{})
+		go func() {
+			app.GRPC.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			log.WithError(ctx.Err()).Errorln("failed draining gRPC server")
+			app.GRPC.Stop()
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		select {
+		case <-workersDone:
+		case <-ctx.Done():
+			log.WithError(ctx.Err()).Errorln("failed stopping background workers")
+		}
+	}()
+
+	wg.Wait()
+
+	closed := make(chan struct{})
+	go func() {
+		app.DB.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		log.WithError(ctx.Err()).Errorln("failed closing postgres connections")
+	}
+
+	err := app.FlushTraces(ctx)
+	if err != nil {
+		log.WithError(err).Errorln("failed flushing traces")
+	}
 }
@@ -28,9 +28,373 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/accounts": {
+        "/admin/v1/accounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List accounts matching the filters, paginated by cursor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Lists accounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Accounts created since this RFC3339 date (inclusive)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Accounts created until this RFC3339 date (exclusive)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account main currency, BRL by default if balance bounds are given",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum main balance (inclusive), an integer in minor units or a decimal in major ones, e.g. 1050 or 10.50",
+                        "name": "min_balance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum main balance (inclusive), an integer in minor units or a decimal in major ones, e.g. 1050 or 10.50",
+                        "name": "max_balance",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "blocked",
+                            "closed"
+                        ],
+                        "type": "string",
+                        "description": "Account status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Leading part of the owner CPF or CNPJ",
+                        "name": "document_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at",
+                            "balance",
+                            "-balance"
+                        ],
+                        "type": "string",
+                        "description": "Sorting, prefixed by - for descending order (default -created_at), balance meaning the main one, sorted within its currency",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.ListAccountsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Could not parse request"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/admin/v1/accounts/{account_id}/block": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Prevents an active account from moving money",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Blocks an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.GetAccountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Could not parse request"
+                    },
+                    "404": {
+                        "description": "Account not found"
+                    },
+                    "409": {
+                        "description": "Account is not active"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/admin/v1/accounts/{account_id}/close": {
             "post": {
-                "description": "Creates an bank account",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Closes an active or blocked account for good",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Closes an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.GetAccountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Could not parse request"
+                    },
+                    "404": {
+                        "description": "Account not found"
+                    },
+                    "409": {
+                        "description": "Account already closed"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/admin/v1/accounts/{account_id}/credit-limit": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets a new credit limit on the balance in its currency keeping the already reserved credit, which the new limit can't be lower than.\nThe change is audited as made by the token subject.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Changes an account credit limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/accounts.UpdateCreditLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.GetAccountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Could not parse request"
+                    },
+                    "404": {
+                        "description": "Account not found"
+                    },
+                    "422": {
+                        "description": "Could not change credit limit"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/admin/v1/accounts/{account_id}/unblock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows a blocked account to move money again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Unblocks an account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.GetAccountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Could not parse request"
+                    },
+                    "404": {
+                        "description": "Account not found"
+                    },
+                    "409": {
+                        "description": "Account is not blocked"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/api/v1/accounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve an account by its owner document, either formatted or digits only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "summary": "Gets an account by document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Owner CPF or CNPJ",
+                        "name": "document_number",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.GetAccountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Could not parse request"
+                    },
+                    "404": {
+                        "description": "Account not found"
+                    },
+                    "422": {
+                        "description": "Invalid document"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an bank account holding balances in the credit limit currency, its main one, plus any other listed",
                 "consumes": [
                     "application/json"
                 ],
@@ -74,8 +438,13 @@ var doc = `{
                 }
             }
         },
-        "/accounts/{account_id}": {
+        "/api/v1/accounts/{account_id}": {
             "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "description": "Retrieve an account by its ID",
                 "consumes": [
                     "application/json"
@@ -117,9 +486,100 @@ var doc = `{
                     }
                 }
             }
+        },
+        "/api/v1/accounts/{account_id}/statement": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve account movements, newest first, paginated by cursor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Accounts"
+                ],
+                "summary": "Gets an account statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movements since this RFC3339 date (inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movements until this RFC3339 date (exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/accounts.GetStatementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Could not parse request"
+                    },
+                    "404": {
+                        "description": "Account not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "accounts.BalanceResponse": {
+            "type": "object",
+            "properties": {
+                "available_balance": {
+                    "type": "integer"
+                },
+                "available_credit_limit": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "integer"
+                },
+                "credit_limit": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "BRL"
+                }
+            }
+        },
         "accounts.CreateAccountRequest": {
             "type": "object",
             "required": [
@@ -130,9 +590,23 @@ var doc = `{
                     "type": "integer",
                     "example": 15000
                 },
+                "currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "USD",
+                        "EUR"
+                    ]
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "BRL"
+                },
                 "document_number": {
                     "type": "string",
-                    "example": "12345678900"
+                    "example": "123.456.789-09"
                 }
             }
         },
@@ -150,22 +624,136 @@ var doc = `{
                 "account_id": {
                     "type": "string"
                 },
+                "available_balance": {
+                    "type": "integer"
+                },
                 "available_credit_limit": {
                     "type": "integer"
                 },
                 "balance": {
                     "type": "integer"
                 },
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accounts.BalanceResponse"
+                    }
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "credit_limit": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "BRL"
+                },
                 "document_number": {
                     "type": "string"
                 },
+                "document_type": {
+                    "type": "string",
+                    "example": "cpf"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
+        },
+        "accounts.GetStatementResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accounts.StatementEntry"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.ListAccountsResponse": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/accounts.GetAccountResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.StatementEntry": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "available_credit_limit": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "integer"
+                },
+                "counterpart_account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "BRL"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "deposit"
+                },
+                "transaction_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "accounts.UpdateCreditLimitRequest": {
+            "type": "object",
+            "required": [
+                "credit_limit",
+                "reason"
+            ],
+            "properties": {
+                "credit_limit": {
+                    "type": "integer",
+                    "example": 20000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "BRL"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "customer income increased"
+                }
+            }
+        },
+        "vos.Money": {
+            "type": "object"
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -183,7 +771,7 @@ type swaggerInfo struct {
 var SwaggerInfo = swaggerInfo{
 	Version:     "1.0",
 	Host:        "localhost:3001",
-	BasePath:    "/api/v1",
+	BasePath:    "/",
 	Schemes:     []string{"http"},
 	Title:       "Swagger Mybank API",
 	Description: "Documentation Mybank API",
@@ -1,21 +1,28 @@
 package app
 
 import (
+	"github.com/fernandodr19/mybank-acc/pkg/config"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/db/postgres"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/events"
 
-	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 // App contains application's usecases
 type App struct {
 	Accounts *accounts.Usecase
+
+	// Broker delivers balance updates to account watchers
+	Broker *events.Broker
 }
 
 // BuildApp builds application struct with its necessary usecases
-func BuildApp(dbConn *pgx.Conn) *App {
+func BuildApp(dbConn *pgxpool.Pool, holds config.Holds) *App {
 	accRepo := postgres.NewAccountsRepository(dbConn)
+	broker := events.NewBroker()
 	return &App{
-		Accounts: accounts.NewUsecase(accRepo),
+		Accounts: accounts.NewUsecase(accRepo, broker, holds.TTL),
+		Broker:   broker,
 	}
 }
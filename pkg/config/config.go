@@ -14,6 +14,10 @@ type Config struct {
 	GRPC
 	Swagger
 	Postgres
+	Auth
+	Tracing
+	Outbox
+	Holds
 }
 
 // API defines api configuration
@@ -30,6 +34,17 @@ func (a API) Address() string {
 type GRPC struct {
 	Protocol string `envconfig:"GRPC_PROTOCOL" default:"tcp"`
 	Port     string `envconfig:"GRPC_PORT" default:"9000"`
+
+	// TLS is enabled once server certificate and key are provided.
+	// Client certificates are required and verified against ClientCAFile when it is set (mutual TLS).
+	CertFile     string `envconfig:"GRPC_TLS_CERT_FILE"`
+	KeyFile      string `envconfig:"GRPC_TLS_KEY_FILE"`
+	ClientCAFile string `envconfig:"GRPC_TLS_CLIENT_CA_FILE"`
+}
+
+// TLSEnabled tells whether the server has a certificate to serve TLS with
+func (g GRPC) TLSEnabled() bool {
+	return g.CertFile != "" && g.KeyFile != ""
 }
 
 // Address returns GRPC address
@@ -37,6 +52,41 @@ func (g GRPC) Address() string {
 	return fmt.Sprintf(":%s", g.Port)
 }
 
+// Auth defines how access tokens are validated.
+// Tokens are signed either with a shared HMAC secret or with a RSA key whose public part is configured here.
+type Auth struct {
+	HMACSecret       string `envconfig:"AUTH_HMAC_SECRET"`
+	RSAPublicKeyFile string `envconfig:"AUTH_RSA_PUBLIC_KEY_FILE"`
+	Issuer           string `envconfig:"AUTH_ISSUER"`
+	Audience         string `envconfig:"AUTH_AUDIENCE"`
+}
+
+// Tracing defines where traces are exported to.
+// Exporter is one of none, stdout (local debugging) or otlp.
+type Tracing struct {
+	Exporter     string  `envconfig:"TRACING_EXPORTER" default:"none"`
+	OTLPEndpoint string  `envconfig:"TRACING_OTLP_ENDPOINT" default:"localhost:4317"`
+	OTLPInsecure bool    `envconfig:"TRACING_OTLP_INSECURE" default:"false"`
+	SampleRatio  float64 `envconfig:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+// Outbox defines how account events are relayed to downstream services.
+// Publisher is one of log or file, the latter appending events as JSON lines to File.
+type Outbox struct {
+	Publisher    string        `envconfig:"OUTBOX_PUBLISHER" default:"log"`
+	File         string        `envconfig:"OUTBOX_FILE" default:"events.jsonl"`
+	PollInterval time.Duration `envconfig:"OUTBOX_POLL_INTERVAL" default:"1s"`
+	BatchSize    int           `envconfig:"OUTBOX_BATCH_SIZE" default:"100"`
+}
+
+// Holds defines how long funds stay held and how often expired holds are released.
+// Each sweep releases batches of expired holds until one comes out short.
+type Holds struct {
+	TTL           time.Duration `envconfig:"HOLDS_TTL" default:"168h"`
+	SweepInterval time.Duration `envconfig:"HOLDS_SWEEP_INTERVAL" default:"1m"`
+	BatchSize     int           `envconfig:"HOLDS_SWEEP_BATCH_SIZE" default:"100"`
+}
+
 type Swagger struct {
 	Host string `envconfig:"SWAGGER_HOST" default:"0.0.0.0:3001"`
 }
@@ -49,6 +99,13 @@ type Postgres struct {
 	Port     string `envconfig:"DATABASE_PORT" default:"5434"`
 	DBName   string `envconfig:"DATABASE_NAME" default:"mybankacc"`
 	SSLMode  string `envconfig:"DATABASE_SSLMODE" default:"sslmode=disable"`
+
+	// connection pool settings
+	MaxConns          int32         `envconfig:"DATABASE_MAX_CONNS" default:"20"`
+	MinConns          int32         `envconfig:"DATABASE_MIN_CONNS" default:"2"`
+	MaxConnLifetime   time.Duration `envconfig:"DATABASE_MAX_CONN_LIFETIME" default:"1h"`
+	MaxConnIdleTime   time.Duration `envconfig:"DATABASE_MAX_CONN_IDLE_TIME" default:"30m"`
+	HealthCheckPeriod time.Duration `envconfig:"DATABASE_HEALTH_CHECK_PERIOD" default:"1m"`
 }
 
 // URL builds postgres URL
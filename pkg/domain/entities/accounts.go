@@ -1,25 +1,124 @@
 package entities
 
 import (
+	"sort"
 	"time"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
 )
 
+// AccountStatus is the lifecycle status of an account
+type AccountStatus string
+
+const (
+	AccountStatusActive  AccountStatus = "active"
+	AccountStatusBlocked AccountStatus = "blocked"
+	AccountStatusClosed  AccountStatus = "closed"
+)
+
+// String returns account status as string
+func (s AccountStatus) String() string {
+	return string(s)
+}
+
+// StatusTransition describes from which statuses an account may move to another one
+type StatusTransition struct {
+	From []AccountStatus
+	To   AccountStatus
+}
+
+// Allowed account status transitions. Only active accounts can move money.
+var (
+	BlockTransition   = StatusTransition{From: []AccountStatus{AccountStatusActive}, To: AccountStatusBlocked}
+	UnblockTransition = StatusTransition{From: []AccountStatus{AccountStatusBlocked}, To: AccountStatusActive}
+	CloseTransition   = StatusTransition{From: []AccountStatus{AccountStatusActive, AccountStatusBlocked}, To: AccountStatusClosed}
+)
+
 // Account entity
 type Account struct {
-	ID              vos.AccountID
-	Document        vos.Document
-	Balance         vos.Money
+	ID           vos.AccountID
+	Document     vos.Document
+	DocumentType vos.DocumentType
+	Currency     vos.Currency // main currency of the account
+	Balances     []Balance    // one per currency held, sorted by currency
+	Status       AccountStatus
+	CreatedAt    time.Time
+	UpdateAt     time.Time
+}
+
+// Balance holds the amounts of an account in a single currency
+type Balance struct {
+	Balance         vos.Money // ledger balance, held funds included
+	Held            vos.Money // funds held by active holds, never exceeding the balance
+	CreditLimit     vos.Money
 	AvailableCredit vos.Money
-	CreatedAt       time.Time
-	UpdateAt        time.Time
 }
 
-func NewAccount(doc vos.Document, balance vos.Money, AvailableCredit vos.Money) Account {
-	return Account{
-		Document:        doc,
-		Balance:         balance,
-		AvailableCredit: AvailableCredit,
+// NewBalance builds an empty balance with its whole credit limit available
+func NewBalance(creditLimit vos.Money) Balance {
+	return Balance{
+		Balance:         vos.NewMoney(0, creditLimit.Currency()),
+		Held:            vos.NewMoney(0, creditLimit.Currency()),
+		CreditLimit:     creditLimit,
+		AvailableCredit: creditLimit,
 	}
 }
+
+// Currency returns the currency the balance is kept in
+func (b Balance) Currency() vos.Currency {
+	return b.Balance.Currency()
+}
+
+// AvailableBalance is the part of the balance not held, which can be moved
+func (b Balance) AvailableBalance() vos.Money {
+	// held funds are never negative nor above the balance, so this can't overflow
+	available, _ := b.Balance.Sub(b.Held)
+	return available
+}
+
+// ReservedCredit is the part of the credit limit currently in use
+func (b Balance) ReservedCredit() (vos.Money, error) {
+	return b.CreditLimit.Sub(b.AvailableCredit)
+}
+
+// NewAccount builds an account holding balances in the credit limit currency, its main one, plus any other given.
+// The whole credit limit is available from the start.
+func NewAccount(doc vos.Document, balance vos.Money, creditLimit vos.Money, currencies ...vos.Currency) Account {
+	main := NewBalance(creditLimit)
+	main.Balance = vos.NewMoney(balance.Int64(), creditLimit.Currency())
+
+	acc := Account{
+		Document:     doc,
+		DocumentType: doc.Type(),
+		Currency:     creditLimit.Currency(),
+		Balances:     []Balance{main},
+		Status:       AccountStatusActive,
+	}
+
+	for _, currency := range currencies {
+		if _, ok := acc.BalanceIn(currency); !ok {
+			acc.Balances = append(acc.Balances, NewBalance(vos.NewMoney(0, currency)))
+		}
+	}
+	sort.Slice(acc.Balances, func(i, j int) bool {
+		return acc.Balances[i].Currency() < acc.Balances[j].Currency()
+	})
+
+	return acc
+}
+
+// BalanceIn returns the account balance in the currency, telling whether the account holds it at all
+func (a Account) BalanceIn(currency vos.Currency) (Balance, bool) {
+	for _, balance := range a.Balances {
+		if balance.Currency() == currency {
+			return balance, true
+		}
+	}
+	return NewBalance(vos.NewMoney(0, currency)), false
+}
+
+// MainBalance returns the account balance in its main currency
+func (a Account) MainBalance() Balance {
+	balance, _ := a.BalanceIn(a.Currency)
+	return balance
+}
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+)
+
+// CreditLimitChange is the audit record of a credit limit adjustment
+type CreditLimitChange struct {
+	ID            string
+	AccountID     vos.AccountID
+	PreviousLimit vos.Money
+	NewLimit      vos.Money
+	ChangedBy     string
+	Reason        string
+	CreatedAt     time.Time
+}
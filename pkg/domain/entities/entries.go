@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+)
+
+// Operation describes what moved money on an account
+type Operation string
+
+const (
+	OperationDeposit           Operation = "deposit"
+	OperationWithdrawal        Operation = "withdrawal"
+	OperationCreditReservation Operation = "credit_reservation"
+	OperationCreditRelease     Operation = "credit_release"
+	OperationTransferOut       Operation = "transfer_out"
+	OperationTransferIn        Operation = "transfer_in"
+)
+
+// String returns operation as string
+func (o Operation) String() string {
+	return string(o)
+}
+
+// Entry is an immutable ledger record of an account balance change
+type Entry struct {
+	ID              vos.TransactionID
+	AccountID       vos.AccountID
+	CounterpartID   vos.AccountID // the other account of a transfer
+	Operation       Operation
+	Amount          vos.Money
+	Balance         vos.Money // balance in the amount currency right after the operation
+	AvailableCredit vos.Money // available credit in the amount currency right after the operation
+	Held            vos.Money // funds on hold in the amount currency right after the operation
+	IdempotencyKey  vos.IdempotencyKey
+	CreatedAt       time.Time
+}
+
+// BalanceUpdate carries the account amounts right after a movement or a hold change
+type BalanceUpdate struct {
+	AccountID       vos.AccountID
+	EntryID         vos.TransactionID
+	Operation       Operation
+	HoldID          vos.HoldID // hold changed, placed and released ones registering no entry
+	HoldStatus      HoldStatus
+	Balance         vos.Money
+	AvailableCredit vos.Money
+	Held            vos.Money
+}
+
+// AvailableBalance is the part of the balance not held
+func (u BalanceUpdate) AvailableBalance() vos.Money {
+	return Balance{Balance: u.Balance, Held: u.Held}.AvailableBalance()
+}
+
+// NewBalanceUpdate builds the balance update of a just registered ledger entry
+func NewBalanceUpdate(entry Entry) BalanceUpdate {
+	return BalanceUpdate{
+		AccountID:       entry.AccountID,
+		EntryID:         entry.ID,
+		Operation:       entry.Operation,
+		Balance:         entry.Balance,
+		AvailableCredit: entry.AvailableCredit,
+		Held:            entry.Held,
+	}
+}
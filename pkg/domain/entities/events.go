@@ -0,0 +1,149 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+)
+
+// EventType describes what happened to an account
+type EventType string
+
+const (
+	EventAccountCreated  EventType = "account.created"
+	EventAccountCredited EventType = "account.credited"
+	EventAccountDebited  EventType = "account.debited"
+	EventCreditReserved  EventType = "account.credit_reserved"
+	EventCreditReleased  EventType = "account.credit_released"
+	EventHoldPlaced      EventType = "account.hold_placed"
+	EventHoldCaptured    EventType = "account.hold_captured"
+	EventHoldVoided      EventType = "account.hold_voided"
+	EventHoldExpired     EventType = "account.hold_expired"
+)
+
+// String returns event type as string
+func (t EventType) String() string {
+	return string(t)
+}
+
+// Event is a change on an account which downstream services are notified about.
+// Events are delivered at least once and in order per account, so consumers should dedupe them by ID.
+type Event struct {
+	ID        string        `json:"id"`
+	AccountID vos.AccountID `json:"account_id"`
+	Type      EventType     `json:"type"`
+	Data      interface{}   `json:"data"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// AccountCreatedData is the data of account.created events
+type AccountCreatedData struct {
+	AccountID    vos.AccountID    `json:"account_id"`
+	DocumentType vos.DocumentType `json:"document_type,omitempty"`
+	Currency     vos.Currency     `json:"currency"`
+	Currencies   []vos.Currency   `json:"currencies"`   // every currency the account holds balances in
+	Balance      vos.Money        `json:"balance"`      // in the main currency
+	CreditLimit  vos.Money        `json:"credit_limit"` // in the main currency
+}
+
+// EntryData is the data of events triggered by ledger entries
+type EntryData struct {
+	EntryID         vos.TransactionID `json:"entry_id"`
+	AccountID       vos.AccountID     `json:"account_id"`
+	CounterpartID   vos.AccountID     `json:"counterpart_id,omitempty"`
+	Operation       Operation         `json:"operation"`
+	Currency        vos.Currency      `json:"currency"`
+	Amount          vos.Money         `json:"amount"`
+	Balance         vos.Money         `json:"balance"`
+	AvailableCredit vos.Money         `json:"available_credit"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+// HoldData is the data of events triggered by holds
+type HoldData struct {
+	HoldID         vos.HoldID        `json:"hold_id"`
+	AccountID      vos.AccountID     `json:"account_id"`
+	Status         HoldStatus        `json:"status"`
+	Currency       vos.Currency      `json:"currency"`
+	Amount         vos.Money         `json:"amount"`
+	CapturedAmount vos.Money         `json:"captured_amount"`
+	EntryID        vos.TransactionID `json:"entry_id,omitempty"` // withdrawal registered on capture
+	ExpiresAt      time.Time         `json:"expires_at"`
+}
+
+// NewAccountCreatedEvent builds the event of a just created account
+func NewAccountCreatedEvent(acc Account) Event {
+	currencies := make([]vos.Currency, 0, len(acc.Balances))
+	for _, balance := range acc.Balances {
+		currencies = append(currencies, balance.Currency())
+	}
+
+	main := acc.MainBalance()
+	return Event{
+		AccountID: acc.ID,
+		Type:      EventAccountCreated,
+		Data: AccountCreatedData{
+			AccountID:    acc.ID,
+			DocumentType: acc.DocumentType,
+			Currency:     acc.Currency,
+			Currencies:   currencies,
+			Balance:      main.Balance,
+			CreditLimit:  main.CreditLimit,
+		},
+	}
+}
+
+// entryEvents maps ledger operations to the event they trigger
+var entryEvents = map[Operation]EventType{
+	OperationDeposit:           EventAccountCredited,
+	OperationTransferIn:        EventAccountCredited,
+	OperationWithdrawal:        EventAccountDebited,
+	OperationTransferOut:       EventAccountDebited,
+	OperationCreditReservation: EventCreditReserved,
+	OperationCreditRelease:     EventCreditReleased,
+}
+
+// NewEntryEvent builds the event of a just registered ledger entry
+func NewEntryEvent(entry Entry) Event {
+	return Event{
+		AccountID: entry.AccountID,
+		Type:      entryEvents[entry.Operation],
+		Data: EntryData{
+			EntryID:         entry.ID,
+			AccountID:       entry.AccountID,
+			CounterpartID:   entry.CounterpartID,
+			Operation:       entry.Operation,
+			Currency:        entry.Amount.Currency(),
+			Amount:          entry.Amount,
+			Balance:         entry.Balance,
+			AvailableCredit: entry.AvailableCredit,
+			CreatedAt:       entry.CreatedAt,
+		},
+	}
+}
+
+// holdEvents maps hold statuses to the event a hold reaching them triggers
+var holdEvents = map[HoldStatus]EventType{
+	HoldStatusActive:   EventHoldPlaced,
+	HoldStatusCaptured: EventHoldCaptured,
+	HoldStatusVoided:   EventHoldVoided,
+	HoldStatusExpired:  EventHoldExpired,
+}
+
+// NewHoldEvent builds the event of a hold that just got placed or reached its final status
+func NewHoldEvent(hold Hold) Event {
+	return Event{
+		AccountID: hold.AccountID,
+		Type:      holdEvents[hold.Status],
+		Data: HoldData{
+			HoldID:         hold.ID,
+			AccountID:      hold.AccountID,
+			Status:         hold.Status,
+			Currency:       hold.Amount.Currency(),
+			Amount:         hold.Amount,
+			CapturedAmount: hold.CapturedAmount,
+			EntryID:        hold.EntryID,
+			ExpiresAt:      hold.ExpiresAt,
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+)
+
+// HoldStatus is the lifecycle status of a hold
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusVoided   HoldStatus = "voided"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+// String returns hold status as string
+func (s HoldStatus) String() string {
+	return string(s)
+}
+
+// Hold keeps funds of an account balance aside, e.g. for a card purchase authorized but not settled yet.
+// Held funds leave the available balance without moving money until the hold is captured, voided or expires.
+type Hold struct {
+	ID             vos.HoldID
+	AccountID      vos.AccountID
+	Amount         vos.Money
+	CapturedAmount vos.Money         // withdrawn on capture, whatever remains being released
+	EntryID        vos.TransactionID // withdrawal registered on capture
+	Status         HoldStatus
+	IdempotencyKey vos.IdempotencyKey
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewHold builds an active hold of the amount lasting until expiresAt
+func NewHold(key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money, expiresAt time.Time) Hold {
+	return Hold{
+		AccountID:      accID,
+		Amount:         amount,
+		CapturedAmount: vos.NewMoney(0, amount.Currency()),
+		Status:         HoldStatusActive,
+		IdempotencyKey: key,
+		ExpiresAt:      expiresAt,
+	}
+}
+
+// IsActive tells whether the hold still keeps its funds aside and can be captured at the given time
+func (h Hold) IsActive(now time.Time) bool {
+	return h.Status == HoldStatusActive && now.Before(h.ExpiresAt)
+}
+
+// NewHoldBalanceUpdate builds the balance update of a hold just placed, captured, voided or expired
+func NewHoldBalanceUpdate(hold Hold, balance Balance) BalanceUpdate {
+	return BalanceUpdate{
+		AccountID:       hold.AccountID,
+		HoldID:          hold.ID,
+		HoldStatus:      hold.Status,
+		Balance:         balance.Balance,
+		AvailableCredit: balance.AvailableCredit,
+		Held:            balance.Held,
+	}
+}
+
+// NewCaptureBalanceUpdate builds the balance update of a hold just captured into the entry
+func NewCaptureBalanceUpdate(hold Hold, entry Entry) BalanceUpdate {
+	update := NewHoldBalanceUpdate(hold, Balance{
+		Balance:         entry.Balance,
+		AvailableCredit: entry.AvailableCredit,
+		Held:            entry.Held,
+	})
+	update.EntryID = entry.ID
+	update.Operation = entry.Operation
+	return update
+}
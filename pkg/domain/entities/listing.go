@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+)
+
+// AccountSort orders an accounts listing, a leading "-" meaning descending order
+type AccountSort string
+
+const (
+	SortByCreatedAt     AccountSort = "created_at"
+	SortByCreatedAtDesc AccountSort = "-created_at"
+	SortByBalance       AccountSort = "balance"
+	SortByBalanceDesc   AccountSort = "-balance"
+)
+
+// String returns account sort as string
+func (s AccountSort) String() string {
+	return string(s)
+}
+
+// AccountCursor points to an account within a listing.
+// Only the sorting column and the ID are taken into account, balances being the ones in the account main currency.
+type AccountCursor struct {
+	CreatedAt time.Time
+	Balance   vos.Money
+	ID        vos.AccountID
+}
+
+// IsZero tells whether the cursor points to nowhere
+func (c AccountCursor) IsZero() bool {
+	return c.CreatedAt.IsZero() && c.Balance == vos.Money{} && c.ID == ""
+}
+
+// AccountFilter narrows down and orders an accounts listing
+type AccountFilter struct {
+	CreatedFrom    time.Time     // inclusive, ignored if zero
+	CreatedTo      time.Time     // exclusive, ignored if zero
+	MinBalance     *vos.Money    // inclusive, compared to the main balance of accounts in its currency, ignored if nil
+	MaxBalance     *vos.Money    // inclusive, compared to the main balance of accounts in its currency, ignored if nil
+	Status         AccountStatus // ignored if empty
+	Currency       vos.Currency  // main currency, ignored if empty
+	DocumentPrefix string        // ignored if empty
+	Sort           AccountSort
+	After          AccountCursor // accounts following the cursor in sort order, ignored if zero
+	Limit          int
+}
+
+// AccountsPage is a page of an accounts listing
+type AccountsPage struct {
+	Accounts []Account
+	Next     *AccountCursor // nil on the last page
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+)
+
+// EntryCursor points to an entry within an account statement
+type EntryCursor struct {
+	CreatedAt time.Time
+	ID        vos.TransactionID
+}
+
+// IsZero tells whether the cursor points to nowhere
+func (c EntryCursor) IsZero() bool {
+	return c.CreatedAt.IsZero() && c.ID == ""
+}
+
+// StatementFilter narrows down the entries of an account statement
+type StatementFilter struct {
+	From   time.Time   // inclusive, ignored if zero
+	To     time.Time   // exclusive, ignored if zero
+	Before EntryCursor // entries older than the cursor, ignored if zero
+	Limit  int
+}
+
+// Statement is a page of an account's entries in reverse chronological order
+type Statement struct {
+	Entries []Entry
+	Next    *EntryCursor // nil on the last page
+}
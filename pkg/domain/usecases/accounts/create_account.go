@@ -2,7 +2,6 @@ package accounts
 
 import (
 	"context"
-	"strconv"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
@@ -11,26 +10,44 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// CreateAccount creates an account
-func (u Usecase) CreateAccount(ctx context.Context, doc vos.Document, creditLimit vos.Money) (vos.AccountID, error) {
+// CreateAccount creates an account whose main currency is the credit limit one, also holding balances in any other currency given
+func (u Usecase) CreateAccount(ctx context.Context, doc vos.Document, creditLimit vos.Money, currencies ...vos.Currency) (_ vos.AccountID, err error) {
 	const operation = "accounts.Usecase.CreateAccount"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
 
 	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
-		"doc": doc,
+		"doc":        doc,
+		"currency":   creditLimit.Currency(),
+		"currencies": currencies,
 	})
 
 	log.Infoln("creating account")
 
-	_, err := strconv.Atoi(doc.String())
+	doc, err = vos.NewDocument(doc.String())
 	if err != nil {
-		return "", ErrInvalidDocument
+		return "", invalidDocumentError{reason: err}
 	}
 
-	if creditLimit < 0 {
+	creditLimit, err = normalizeMoney(creditLimit)
+	if err != nil {
+		return "", err
+	}
+
+	if creditLimit.Sign() < 0 {
 		return "", ErrInvalidCreditLimit
 	}
 
-	acc := entities.NewAccount(doc, 0, creditLimit)
+	held := make([]vos.Currency, 0, len(currencies))
+	for _, currency := range currencies {
+		currency, err = vos.NewCurrency(currency.String())
+		if err != nil {
+			return "", ErrInvalidCurrency
+		}
+		held = append(held, currency)
+	}
+
+	acc := entities.NewAccount(doc, vos.NewMoney(0, creditLimit.Currency()), creditLimit, held...)
 	accID, err := u.accRepo.CreateAccount(ctx, acc)
 	if err != nil {
 		return "", domain.Error(operation, err)
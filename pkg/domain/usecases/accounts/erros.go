@@ -3,12 +3,50 @@ package accounts
 import "errors"
 
 var (
-	ErrAccountNotFound     = errors.New("account not found")
-	ErrAccountConflict     = errors.New("account alreagy regiteres")
-	ErrInvalidAccID        = errors.New("invalid account id")
-	ErrInvalidCreditLimit  = errors.New("invalid credit limit")
-	ErrInvalidDocument     = errors.New("invalid document")
-	ErrInvalidAmount       = errors.New("invalid amount")
-	ErrInsufficientBalance = errors.New("insufficient balance")
-	ErrInsufficientCredit  = errors.New("insufficient credit")
+	ErrAccountNotFound          = errors.New("account not found")
+	ErrAccountConflict          = errors.New("account alreagy regiteres")
+	ErrAccountNotActive         = errors.New("account not active")
+	ErrInvalidAccID             = errors.New("invalid account id")
+	ErrInvalidCreditLimit       = errors.New("invalid credit limit")
+	ErrCreditLimitBelowReserved = errors.New("credit limit below reserved credit")
+	ErrInvalidCreditLimitChange = errors.New("credit limit change must have an author and a reason")
+	ErrInvalidDocument          = errors.New("invalid document")
+	ErrInvalidAmount            = errors.New("invalid amount")
+	ErrAmountOutOfRange         = errors.New("amount out of range")
+	ErrInsufficientBalance      = errors.New("insufficient balance")
+	ErrInsufficientCredit       = errors.New("insufficient credit")
+	ErrCreditLimitExceeded      = errors.New("credit limit exceeded")
+	ErrSameAccountTransfer      = errors.New("can't transfer to the same account")
+	ErrInvalidCurrency          = errors.New("invalid currency")
+	ErrCurrencyMismatch         = errors.New("currency not held by the account")
+
+	ErrInvalidStatementFilter  = errors.New("invalid statement filter")
+	ErrInvalidAccountFilter    = errors.New("invalid account filter")
+	ErrInvalidStatusTransition = errors.New("invalid account status transition")
+	ErrEntryNotFound           = errors.New("entry not found")
+
+	ErrHoldNotFound       = errors.New("hold not found")
+	ErrHoldNotActive      = errors.New("hold no longer active")
+	ErrCaptureExceedsHold = errors.New("capture exceeds the held amount")
+
+	ErrDuplicatedIdempotencyKey = errors.New("idempotency key already processed")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key reused with different parameters")
 )
+
+// invalidDocumentError keeps the precise reason a document was refused
+// while still matching ErrInvalidDocument
+type invalidDocumentError struct {
+	reason error
+}
+
+func (e invalidDocumentError) Error() string {
+	return ErrInvalidDocument.Error() + ": " + e.reason.Error()
+}
+
+func (e invalidDocumentError) Is(target error) bool {
+	return target == ErrInvalidDocument
+}
+
+func (e invalidDocumentError) Unwrap() error {
+	return e.reason
+}
@@ -12,8 +12,10 @@ import (
 )
 
 // GetAccountByID retrieves an account based a given ID
-func (u Usecase) GetAccountByID(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+func (u Usecase) GetAccountByID(ctx context.Context, accID vos.AccountID) (_ entities.Account, err error) {
 	const operation = "accounts.Usecase.GetAccountByID"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
 
 	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
 		"accID": accID,
@@ -30,3 +32,31 @@ func (u Usecase) GetAccountByID(ctx context.Context, accID vos.AccountID) (entit
 
 	return acc, nil
 }
+
+// GetAccountByDocument retrieves the account owned by a given CPF or CNPJ, formatted or not
+func (u Usecase) GetAccountByDocument(ctx context.Context, doc vos.Document) (_ entities.Account, err error) {
+	const operation = "accounts.Usecase.GetAccountByDocument"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"doc": doc,
+	})
+
+	log.Infoln("getting account by document")
+
+	// documents are stored normalized
+	doc, err = vos.NewDocument(doc.String())
+	if err != nil {
+		return entities.Account{}, invalidDocumentError{reason: err}
+	}
+
+	acc, err := u.accRepo.GetAccountByDocument(ctx, doc)
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	log.WithField("accID", acc.ID).Infoln("account successfully retrieved")
+
+	return acc, nil
+}
@@ -0,0 +1,69 @@
+package accounts
+
+import (
+	"context"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStatementLimit = 20
+	maxStatementLimit     = 100
+)
+
+// GetStatement retrieves a page of the account's entries, newest first
+func (u Usecase) GetStatement(ctx context.Context, accID vos.AccountID, filter entities.StatementFilter) (_ entities.Statement, err error) {
+	const operation = "accounts.Usecase.GetStatement"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"accID": accID,
+	})
+
+	log.Infoln("getting statement")
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultStatementLimit
+	}
+
+	if filter.Limit < 0 || filter.Limit > maxStatementLimit {
+		return entities.Statement{}, ErrInvalidStatementFilter
+	}
+
+	if !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return entities.Statement{}, ErrInvalidStatementFilter
+	}
+
+	_, err = u.accRepo.GetAccountByID(ctx, accID)
+	if err != nil {
+		return entities.Statement{}, domain.Error(operation, err)
+	}
+
+	// fetching an extra entry tells whether there is a next page
+	page := filter
+	page.Limit++
+	entries, err := u.accRepo.ListEntries(ctx, accID, page)
+	if err != nil {
+		return entities.Statement{}, domain.Error(operation, err)
+	}
+
+	statement := entities.Statement{Entries: entries}
+	if len(entries) > filter.Limit {
+		statement.Entries = entries[:filter.Limit]
+		last := statement.Entries[filter.Limit-1]
+		statement.Next = &entities.EntryCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		}
+	}
+
+	log.WithField("entries", len(statement.Entries)).Infoln("statement successfully retrieved")
+
+	return statement, nil
+}
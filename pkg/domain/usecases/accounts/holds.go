@@ -0,0 +1,214 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// PlaceHold keeps funds of an account aside, out of its available balance, until the hold is captured, voided or expires
+func (u Usecase) PlaceHold(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Hold, err error) {
+	const operation = "accounts.Usecase.PlaceHold"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"accID":    accID,
+		"amount":   amount.Int(),
+		"currency": amount.Currency(),
+		"key":      key,
+	})
+
+	log.Infoln("placing a hold")
+
+	amount, err = normalizeMoney(amount)
+	if err != nil {
+		return entities.Hold{}, err
+	}
+
+	if amount.Sign() <= 0 {
+		return entities.Hold{}, ErrInvalidAmount
+	}
+
+	req := entities.NewHold(key, accID, amount, time.Now().Add(u.holdTTL))
+	hold, found, err := u.replayHold(ctx, req)
+	if err != nil {
+		return entities.Hold{}, domain.Error(operation, err)
+	}
+	if found {
+		return hold, nil
+	}
+
+	hold, update, err := u.accRepo.PlaceHold(ctx, req)
+	if errors.Is(err, ErrDuplicatedIdempotencyKey) {
+		// a concurrent request holding the same key got processed first
+		hold, found, err := u.replayHold(ctx, req)
+		if err == nil && !found {
+			err = ErrDuplicatedIdempotencyKey
+		}
+		if err != nil {
+			return entities.Hold{}, domain.Error(operation, err)
+		}
+		return hold, nil
+	}
+	if err != nil {
+		return entities.Hold{}, domain.Error(operation, err)
+	}
+
+	u.broker.Publish(update)
+
+	log.WithField("holdID", hold.ID).Infoln("hold successfully placed")
+
+	return hold, nil
+}
+
+// replayHold looks for a hold already placed under the request idempotency key
+// making sure it was placed by the very same request
+func (u Usecase) replayHold(ctx context.Context, req entities.Hold) (entities.Hold, bool, error) {
+	if req.IdempotencyKey == "" {
+		return entities.Hold{}, false, nil
+	}
+
+	hold, err := u.accRepo.GetHoldByIdempotencyKey(ctx, req.IdempotencyKey)
+	if errors.Is(err, ErrHoldNotFound) {
+		return entities.Hold{}, false, nil
+	}
+	if err != nil {
+		return entities.Hold{}, false, err
+	}
+
+	if hold.AccountID != req.AccountID || hold.Amount != req.Amount {
+		return entities.Hold{}, false, ErrIdempotencyKeyReused
+	}
+
+	logger.FromCtx(ctx).WithField("holdID", hold.ID).Infoln("replaying already placed hold")
+
+	return hold, true, nil
+}
+
+// CaptureHold withdraws the amount, up to the held one, from the account, releasing whatever remains held.
+// A zero amount captures the hold in full, amounts lacking a currency being taken as in the hold one.
+func (u Usecase) CaptureHold(ctx context.Context, key vos.IdempotencyKey, holdID vos.HoldID, amount vos.Money) (_ entities.Entry, err error) {
+	const operation = "accounts.Usecase.CaptureHold"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"holdID":   holdID,
+		"amount":   amount.Int(),
+		"currency": amount.Currency(),
+		"key":      key,
+	})
+
+	log.Infoln("capturing a hold")
+
+	hold, err := u.accRepo.GetHold(ctx, holdID)
+	if err != nil {
+		return entities.Entry{}, domain.Error(operation, err)
+	}
+
+	if amount.Currency() == "" {
+		amount = vos.NewMoney(amount.Int64(), hold.Amount.Currency())
+	}
+	if amount.Sign() == 0 {
+		amount = hold.Amount
+	}
+
+	amount, err = normalizeMoney(amount)
+	if err != nil {
+		return entities.Entry{}, err
+	}
+
+	if amount.Sign() < 0 {
+		return entities.Entry{}, ErrInvalidAmount
+	}
+
+	cmp, err := amount.Cmp(hold.Amount)
+	if err != nil {
+		return entities.Entry{}, ErrCurrencyMismatch
+	}
+	if cmp > 0 {
+		return entities.Entry{}, ErrCaptureExceedsHold
+	}
+
+	entry, err := u.idempotentUpdate(ctx, entities.Entry{
+		IdempotencyKey: key,
+		Operation:      entities.OperationWithdrawal,
+		AccountID:      hold.AccountID,
+		Amount:         amount,
+	}, func() (entities.Entry, entities.BalanceUpdate, error) {
+		entry, captured, err := u.accRepo.CaptureHold(ctx, key, holdID, amount)
+		return entry, entities.NewCaptureBalanceUpdate(captured, entry), err
+	})
+
+	if err != nil {
+		return entities.Entry{}, domain.Error(operation, err)
+	}
+
+	// keys are shared with plain withdrawals, so the entry found must be the very one the hold got captured into
+	if key != "" {
+		hold, err = u.accRepo.GetHold(ctx, holdID)
+		if err != nil {
+			return entities.Entry{}, domain.Error(operation, err)
+		}
+		if hold.EntryID != entry.ID {
+			return entities.Entry{}, ErrIdempotencyKeyReused
+		}
+	}
+
+	log.WithField("txID", entry.ID).Infoln("hold successfully captured")
+
+	return entry, nil
+}
+
+// VoidHold releases the funds of an active hold back to the account available balance
+func (u Usecase) VoidHold(ctx context.Context, holdID vos.HoldID) (_ entities.Hold, err error) {
+	const operation = "accounts.Usecase.VoidHold"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"holdID": holdID,
+	})
+
+	log.Infoln("voiding a hold")
+
+	hold, update, err := u.accRepo.VoidHold(ctx, holdID)
+	if err != nil {
+		return entities.Hold{}, domain.Error(operation, err)
+	}
+
+	u.broker.Publish(update)
+
+	log.Infoln("hold successfully voided")
+
+	return hold, nil
+}
+
+// ExpireHolds releases the funds of up to limit holds past their expiration, returning how many got expired
+func (u Usecase) ExpireHolds(ctx context.Context, limit int) (_ int, err error) {
+	const operation = "accounts.Usecase.ExpireHolds"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	updates, err := u.accRepo.ExpireHolds(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, domain.Error(operation, err)
+	}
+
+	for _, update := range updates {
+		u.broker.Publish(update)
+	}
+
+	if len(updates) > 0 {
+		logger.FromCtx(ctx).WithField("holds", len(updates)).Infoln("holds expired")
+	}
+
+	return len(updates), nil
+}
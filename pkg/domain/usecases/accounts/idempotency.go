@@ -0,0 +1,70 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/metrics"
+)
+
+// idempotent runs process only once per idempotency key, replaying the original
+// entry whenever the same request is received again. Empty keys are never deduplicated.
+func (u Usecase) idempotent(ctx context.Context, req entities.Entry, process func() (entities.Entry, error)) (entities.Entry, error) {
+	return u.idempotentUpdate(ctx, req, func() (entities.Entry, entities.BalanceUpdate, error) {
+		entry, err := process()
+		return entry, entities.NewBalanceUpdate(entry), err
+	})
+}
+
+// idempotentUpdate is idempotent for processes telling themselves the balance update to publish
+func (u Usecase) idempotentUpdate(ctx context.Context, req entities.Entry, process func() (entities.Entry, entities.BalanceUpdate, error)) (entities.Entry, error) {
+	entry, found, err := u.replay(ctx, req)
+	if err != nil || found {
+		return entry, err
+	}
+
+	entry, update, err := process()
+	if errors.Is(err, ErrDuplicatedIdempotencyKey) {
+		// a concurrent request holding the same key got processed first
+		entry, found, err := u.replay(ctx, req)
+		if err == nil && !found {
+			return entities.Entry{}, ErrDuplicatedIdempotencyKey
+		}
+		return entry, err
+	}
+	if err == nil {
+		metrics.CountMovement(req.Operation.String(), req.Amount.Currency().String(), req.Amount.Int64())
+		u.broker.Publish(update)
+	}
+
+	return entry, err
+}
+
+// replay looks for an entry already registered under the request idempotency key
+// making sure it was originated by the very same request
+func (u Usecase) replay(ctx context.Context, req entities.Entry) (entities.Entry, bool, error) {
+	if req.IdempotencyKey == "" {
+		return entities.Entry{}, false, nil
+	}
+
+	entry, err := u.accRepo.GetEntryByIdempotencyKey(ctx, req.IdempotencyKey)
+	if errors.Is(err, ErrEntryNotFound) {
+		return entities.Entry{}, false, nil
+	}
+	if err != nil {
+		return entities.Entry{}, false, err
+	}
+
+	if entry.Operation != req.Operation ||
+		entry.AccountID != req.AccountID ||
+		entry.CounterpartID != req.CounterpartID ||
+		entry.Amount != req.Amount {
+		return entities.Entry{}, false, ErrIdempotencyKeyReused
+	}
+
+	logger.FromCtx(ctx).WithField("txID", entry.ID).Infoln("replaying already processed request")
+
+	return entry, true, nil
+}
@@ -0,0 +1,67 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/metrics"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/tracing"
+)
+
+// instrument opens a span named after the usecase operation.
+// The returned function ends it and counts the failure, if any, so it must be deferred.
+func instrument(ctx context.Context, operation string, err *error) (context.Context, func()) {
+	ctx, end := tracing.Trace(ctx, operation, err)
+	return ctx, func() {
+		countError(operation, err)
+		end()
+	}
+}
+
+// errorKeys names domain errors so that they can be used as metric labels
+var errorKeys = []struct {
+	err error
+	key string
+}{
+	{ErrAccountNotFound, "account_not_found"},
+	{ErrAccountConflict, "account_conflict"},
+	{ErrAccountNotActive, "account_not_active"},
+	{ErrInvalidAccID, "invalid_account_id"},
+	{ErrInvalidCreditLimit, "invalid_credit_limit"},
+	{ErrCreditLimitBelowReserved, "credit_limit_below_reserved"},
+	{ErrInvalidCreditLimitChange, "invalid_credit_limit_change"},
+	{ErrInvalidDocument, "invalid_document"},
+	{ErrInvalidAmount, "invalid_amount"},
+	{ErrAmountOutOfRange, "amount_out_of_range"},
+	{ErrInsufficientBalance, "insufficient_balance"},
+	{ErrInsufficientCredit, "insufficient_credit"},
+	{ErrCreditLimitExceeded, "credit_limit_exceeded"},
+	{ErrSameAccountTransfer, "same_account_transfer"},
+	{ErrInvalidCurrency, "invalid_currency"},
+	{ErrCurrencyMismatch, "currency_mismatch"},
+	{ErrInvalidStatementFilter, "invalid_statement_filter"},
+	{ErrInvalidAccountFilter, "invalid_account_filter"},
+	{ErrInvalidStatusTransition, "invalid_status_transition"},
+	{ErrEntryNotFound, "entry_not_found"},
+	{ErrHoldNotFound, "hold_not_found"},
+	{ErrHoldNotActive, "hold_not_active"},
+	{ErrCaptureExceedsHold, "capture_exceeds_hold"},
+	{ErrIdempotencyKeyReused, "idempotency_key_reused"},
+}
+
+// countError counts usecase failures by domain error, anything else is counted as internal
+func countError(operation string, err *error) {
+	if *err == nil {
+		return
+	}
+
+	key := "internal"
+	for _, known := range errorKeys {
+		if errors.Is(*err, known.err) {
+			key = known.key
+			break
+		}
+	}
+
+	metrics.CountUsecaseError(operation, key)
+}
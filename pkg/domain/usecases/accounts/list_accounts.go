@@ -0,0 +1,158 @@
+package accounts
+
+import (
+	"context"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultAccountsLimit = 20
+	maxAccountsLimit     = 100
+)
+
+// ListAccounts retrieves a page of accounts matching the filter, newest first unless sorted otherwise
+func (u Usecase) ListAccounts(ctx context.Context, filter entities.AccountFilter) (_ entities.AccountsPage, err error) {
+	const operation = "accounts.Usecase.ListAccounts"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"sort":     filter.Sort,
+		"status":   filter.Status,
+		"currency": filter.Currency,
+	})
+
+	log.Infoln("listing accounts")
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultAccountsLimit
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = entities.SortByCreatedAtDesc
+	}
+
+	filter, err = normalizeBalanceBounds(filter)
+	if err != nil {
+		return entities.AccountsPage{}, err
+	}
+
+	err = validateAccountFilter(filter)
+	if err != nil {
+		return entities.AccountsPage{}, err
+	}
+
+	// documents are stored normalized
+	if filter.DocumentPrefix != "" {
+		filter.DocumentPrefix, err = vos.NewDocumentPrefix(filter.DocumentPrefix)
+		if err != nil {
+			return entities.AccountsPage{}, ErrInvalidAccountFilter
+		}
+	}
+
+	// fetching an extra account tells whether there is a next page
+	page := filter
+	page.Limit++
+	accs, err := u.accRepo.ListAccounts(ctx, page)
+	if err != nil {
+		return entities.AccountsPage{}, domain.Error(operation, err)
+	}
+
+	result := entities.AccountsPage{Accounts: accs}
+	if len(accs) > filter.Limit {
+		result.Accounts = accs[:filter.Limit]
+		last := result.Accounts[filter.Limit-1]
+		result.Next = &entities.AccountCursor{
+			CreatedAt: last.CreatedAt,
+			Balance:   last.MainBalance().Balance,
+			ID:        last.ID,
+		}
+	}
+
+	log.WithField("accounts", len(result.Accounts)).Infoln("accounts successfully listed")
+
+	return result, nil
+}
+
+func validateAccountFilter(filter entities.AccountFilter) error {
+	if filter.Limit < 0 || filter.Limit > maxAccountsLimit {
+		return ErrInvalidAccountFilter
+	}
+
+	if !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return ErrInvalidAccountFilter
+	}
+
+	if filter.MinBalance != nil && filter.MaxBalance != nil {
+		cmp, err := filter.MinBalance.Cmp(*filter.MaxBalance)
+		if err != nil || cmp > 0 {
+			return ErrInvalidAccountFilter
+		}
+	}
+
+	switch filter.Status {
+	case "", entities.AccountStatusActive, entities.AccountStatusBlocked, entities.AccountStatusClosed:
+	default:
+		return ErrInvalidAccountFilter
+	}
+
+	switch filter.Sort {
+	case entities.SortByCreatedAt, entities.SortByCreatedAtDesc, entities.SortByBalance, entities.SortByBalanceDesc:
+	default:
+		return ErrInvalidAccountFilter
+	}
+
+	return nil
+}
+
+// normalizeBalanceBounds validates the listing currency, which balance bounds imply
+// since balances in different currencies can't be compared
+func normalizeBalanceBounds(filter entities.AccountFilter) (entities.AccountFilter, error) {
+	if filter.Currency != "" {
+		currency, err := vos.NewCurrency(filter.Currency.String())
+		if err != nil {
+			return entities.AccountFilter{}, ErrInvalidAccountFilter
+		}
+		filter.Currency = currency
+	}
+
+	var err error
+	filter.MinBalance, err = normalizeBalanceBound(&filter, filter.MinBalance)
+	if err != nil {
+		return entities.AccountFilter{}, err
+	}
+
+	filter.MaxBalance, err = normalizeBalanceBound(&filter, filter.MaxBalance)
+	if err != nil {
+		return entities.AccountFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// normalizeBalanceBound makes sure the bound is in the listing currency, setting it when not given yet
+func normalizeBalanceBound(filter *entities.AccountFilter, bound *vos.Money) (*vos.Money, error) {
+	if bound == nil {
+		return nil, nil
+	}
+
+	amount, err := normalizeMoney(*bound)
+	if err != nil {
+		return nil, ErrInvalidAccountFilter
+	}
+
+	if filter.Currency == "" {
+		filter.Currency = amount.Currency()
+	}
+	if amount.Currency() != filter.Currency {
+		return nil, ErrInvalidAccountFilter
+	}
+
+	return &amount, nil
+}
@@ -0,0 +1,53 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+)
+
+// normalizeMoney validates the amount currency, amounts lacking one being taken as in vos.DefaultCurrency
+func normalizeMoney(amount vos.Money) (vos.Money, error) {
+	currency, err := vos.NewCurrency(amount.Currency().String())
+	if err != nil {
+		return vos.Money{}, ErrInvalidCurrency
+	}
+
+	return vos.NewMoney(amount.Int64(), currency), nil
+}
+
+// checkCredit makes sure crediting the amount to the picked amount of the account balance doesn't take it out of range.
+// Missing accounts or currencies are left for the repository to report, which also refuses overflows
+// of balances moved concurrently since they were read here.
+func (u Usecase) checkCredit(ctx context.Context, accID vos.AccountID, amount vos.Money, pick func(entities.Balance) vos.Money) error {
+	acc, err := u.accRepo.GetAccountByID(ctx, accID)
+	if errors.Is(err, ErrAccountNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	balance, ok := acc.BalanceIn(amount.Currency())
+	if !ok {
+		return nil
+	}
+
+	_, err = pick(balance).Add(amount)
+	if errors.Is(err, vos.ErrMoneyOverflow) {
+		return ErrAmountOutOfRange
+	}
+	return err
+}
+
+// ledgerBalance picks the balance itself out of the account amounts
+func ledgerBalance(balance entities.Balance) vos.Money {
+	return balance.Balance
+}
+
+// availableCredit picks the credit still available out of the account amounts
+func availableCredit(balance entities.Balance) vos.Money {
+	return balance.AvailableCredit
+}
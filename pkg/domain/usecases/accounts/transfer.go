@@ -0,0 +1,71 @@
+package accounts
+
+import (
+	"context"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// Transfer atomically moves money from one account to another
+func (u Usecase) Transfer(ctx context.Context, key vos.IdempotencyKey, from, to vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
+	const operation = "accounts.Usecase.Transfer"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"from":     from,
+		"to":       to,
+		"amount":   amount.Int(),
+		"currency": amount.Currency(),
+		"key":      key,
+	})
+
+	log.Infoln("processing a transfer")
+
+	amount, err = normalizeMoney(amount)
+	if err != nil {
+		return entities.Entry{}, err
+	}
+
+	if amount.Sign() <= 0 {
+		return entities.Entry{}, ErrInvalidAmount
+	}
+
+	if from == to {
+		return entities.Entry{}, ErrSameAccountTransfer
+	}
+
+	var credit entities.Entry
+	entry, err := u.idempotent(ctx, entities.Entry{
+		IdempotencyKey: key,
+		Operation:      entities.OperationTransferOut,
+		AccountID:      from,
+		CounterpartID:  to,
+		Amount:         amount,
+	}, func() (entities.Entry, error) {
+		if err := u.checkCredit(ctx, to, amount, ledgerBalance); err != nil {
+			return entities.Entry{}, err
+		}
+		debit, in, err := u.accRepo.Transfer(ctx, key, from, to, amount)
+		credit = in
+		return debit, err
+	})
+
+	if err != nil {
+		return entities.Entry{}, domain.Error(operation, err)
+	}
+
+	// the debit is published along with the request, whoever watches the credited account gets its own update.
+	// Replayed transfers leave credit empty, their updates being published back then.
+	if credit.ID != "" {
+		u.broker.Publish(entities.NewBalanceUpdate(credit))
+	}
+
+	log.WithField("txID", entry.ID).Infoln("transfer successfully processed")
+
+	return entry, nil
+}
@@ -4,103 +4,185 @@ import (
 	"context"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
 	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
 	"github.com/sirupsen/logrus"
 )
 
 // Deposit deposits money on an account
-func (u Usecase) Deposit(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+func (u Usecase) Deposit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
 	const operation = "accounts.Usecase.Deposit"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
 
 	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
-		"accID":  accID,
-		"amount": amount.Int(),
+		"accID":    accID,
+		"amount":   amount.Int(),
+		"currency": amount.Currency(),
+		"key":      key,
 	})
 
 	log.Infoln("processing a deposit")
 
-	if amount <= 0 {
-		return ErrInvalidAmount
+	amount, err = normalizeMoney(amount)
+	if err != nil {
+		return entities.Entry{}, err
+	}
+
+	if amount.Sign() <= 0 {
+		return entities.Entry{}, ErrInvalidAmount
 	}
 
-	err := u.accRepo.Deposit(ctx, accID, amount)
+	entry, err := u.idempotent(ctx, entities.Entry{
+		IdempotencyKey: key,
+		Operation:      entities.OperationDeposit,
+		AccountID:      accID,
+		Amount:         amount,
+	}, func() (entities.Entry, error) {
+		if err := u.checkCredit(ctx, accID, amount, ledgerBalance); err != nil {
+			return entities.Entry{}, err
+		}
+		return u.accRepo.Deposit(ctx, key, accID, amount)
+	})
 
 	if err != nil {
-		return domain.Error(operation, err)
+		return entities.Entry{}, domain.Error(operation, err)
 	}
 
-	log.Infoln("deposit successfully processed")
+	log.WithField("txID", entry.ID).Infoln("deposit successfully processed")
 
-	return nil
+	return entry, nil
 }
 
 // Withdraw Withdraws money from an account
-func (u Usecase) Withdraw(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+func (u Usecase) Withdraw(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
 	const operation = "accounts.Usecase.Withdraw"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
 
 	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
-		"accID":  accID,
-		"amount": amount.Int(),
+		"accID":    accID,
+		"amount":   amount.Int(),
+		"currency": amount.Currency(),
+		"key":      key,
 	})
 
 	log.Infoln("processing a withdrawal")
 
-	if amount <= 0 {
-		return ErrInvalidAmount
-	}
-
-	acc, err := u.GetAccountByID(ctx, accID)
+	amount, err = normalizeMoney(amount)
 	if err != nil {
-		return domain.Error(operation, err)
+		return entities.Entry{}, err
 	}
 
-	if acc.Balance < amount {
-		return ErrInsufficientBalance
+	if amount.Sign() <= 0 {
+		return entities.Entry{}, ErrInvalidAmount
 	}
 
-	err = u.accRepo.Withdraw(ctx, accID, amount)
+	entry, err := u.idempotent(ctx, entities.Entry{
+		IdempotencyKey: key,
+		Operation:      entities.OperationWithdrawal,
+		AccountID:      accID,
+		Amount:         amount,
+	}, func() (entities.Entry, error) {
+		// balance is checked by the repository atomically along with the update
+		return u.accRepo.Withdraw(ctx, key, accID, amount)
+	})
 
 	if err != nil {
-		return domain.Error(operation, err)
+		return entities.Entry{}, domain.Error(operation, err)
 	}
 
-	log.Infoln("withdrawal successfully processed")
+	log.WithField("txID", entry.ID).Infoln("withdrawal successfully processed")
 
-	return nil
+	return entry, nil
 }
 
 // ReserveCreditLimit decrease the account's credit limit
-func (u Usecase) ReserveCreditLimit(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+func (u Usecase) ReserveCreditLimit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
 	const operation = "accounts.Usecase.ReserveCreditLimit"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
 
 	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
-		"accID":  accID,
-		"amount": amount.Int(),
+		"accID":    accID,
+		"amount":   amount.Int(),
+		"currency": amount.Currency(),
+		"key":      key,
 	})
 
 	log.Infoln("processing a credit reservation")
 
-	if amount <= 0 {
-		return ErrInvalidAmount
+	amount, err = normalizeMoney(amount)
+	if err != nil {
+		return entities.Entry{}, err
+	}
+
+	if amount.Sign() <= 0 {
+		return entities.Entry{}, ErrInvalidAmount
+	}
+
+	entry, err := u.idempotent(ctx, entities.Entry{
+		IdempotencyKey: key,
+		Operation:      entities.OperationCreditReservation,
+		AccountID:      accID,
+		Amount:         amount,
+	}, func() (entities.Entry, error) {
+		// available credit is checked by the repository atomically along with the update
+		return u.accRepo.DecreaseAvailableCredit(ctx, key, accID, amount)
+	})
+
+	if err != nil {
+		return entities.Entry{}, domain.Error(operation, err)
 	}
 
-	acc, err := u.GetAccountByID(ctx, accID)
+	log.WithField("txID", entry.ID).Infoln("credit limit successfully reserved")
+
+	return entry, nil
+}
+
+// ReleaseCreditLimit gives reserved credit back to the account, never beyond its credit limit
+func (u Usecase) ReleaseCreditLimit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
+	const operation = "accounts.Usecase.ReleaseCreditLimit"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"accID":    accID,
+		"amount":   amount.Int(),
+		"currency": amount.Currency(),
+		"key":      key,
+	})
+
+	log.Infoln("processing a credit release")
+
+	amount, err = normalizeMoney(amount)
 	if err != nil {
-		return domain.Error(operation, err)
+		return entities.Entry{}, err
 	}
 
-	if acc.AvailableCredit < amount {
-		return ErrInsufficientCredit
+	if amount.Sign() <= 0 {
+		return entities.Entry{}, ErrInvalidAmount
 	}
 
-	err = u.accRepo.DecreaseAvailableCredit(ctx, accID, amount)
+	entry, err := u.idempotent(ctx, entities.Entry{
+		IdempotencyKey: key,
+		Operation:      entities.OperationCreditRelease,
+		AccountID:      accID,
+		Amount:         amount,
+	}, func() (entities.Entry, error) {
+		if err := u.checkCredit(ctx, accID, amount, availableCredit); err != nil {
+			return entities.Entry{}, err
+		}
+		// credit limit is checked by the repository atomically along with the update
+		return u.accRepo.IncreaseAvailableCredit(ctx, key, accID, amount)
+	})
 
 	if err != nil {
-		return domain.Error(operation, err)
+		return entities.Entry{}, domain.Error(operation, err)
 	}
 
-	log.Infoln("credit limit successfully reserved")
+	log.WithField("txID", entry.ID).Infoln("credit limit successfully released")
 
-	return nil
+	return entry, nil
 }
@@ -0,0 +1,54 @@
+package accounts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// UpdateCreditLimit adjusts the account credit limit in its currency keeping whatever is already reserved
+func (u Usecase) UpdateCreditLimit(ctx context.Context, accID vos.AccountID, creditLimit vos.Money, changedBy, reason string) (_ entities.Account, err error) {
+	const operation = "accounts.Usecase.UpdateCreditLimit"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"accID":       accID,
+		"creditLimit": creditLimit,
+		"changedBy":   changedBy,
+	})
+
+	log.Infoln("updating credit limit")
+
+	creditLimit, err = normalizeMoney(creditLimit)
+	if err != nil {
+		return entities.Account{}, err
+	}
+
+	if creditLimit.Sign() < 0 {
+		return entities.Account{}, ErrInvalidCreditLimit
+	}
+
+	if strings.TrimSpace(changedBy) == "" || strings.TrimSpace(reason) == "" {
+		return entities.Account{}, ErrInvalidCreditLimitChange
+	}
+
+	acc, err := u.accRepo.UpdateCreditLimit(ctx, entities.CreditLimitChange{
+		AccountID: accID,
+		NewLimit:  creditLimit,
+		ChangedBy: changedBy,
+		Reason:    reason,
+	})
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	log.Infoln("credit limit successfully updated")
+
+	return acc, nil
+}
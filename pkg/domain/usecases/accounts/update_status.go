@@ -0,0 +1,71 @@
+package accounts
+
+import (
+	"context"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// BlockAccount prevents an active account from moving money
+func (u Usecase) BlockAccount(ctx context.Context, accID vos.AccountID) (_ entities.Account, err error) {
+	const operation = "accounts.Usecase.BlockAccount"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	acc, err := u.updateStatus(ctx, accID, entities.BlockTransition)
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	return acc, nil
+}
+
+// UnblockAccount allows a blocked account to move money again
+func (u Usecase) UnblockAccount(ctx context.Context, accID vos.AccountID) (_ entities.Account, err error) {
+	const operation = "accounts.Usecase.UnblockAccount"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	acc, err := u.updateStatus(ctx, accID, entities.UnblockTransition)
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	return acc, nil
+}
+
+// CloseAccount closes an account for good
+func (u Usecase) CloseAccount(ctx context.Context, accID vos.AccountID) (_ entities.Account, err error) {
+	const operation = "accounts.Usecase.CloseAccount"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	acc, err := u.updateStatus(ctx, accID, entities.CloseTransition)
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	return acc, nil
+}
+
+func (u Usecase) updateStatus(ctx context.Context, accID vos.AccountID, transition entities.StatusTransition) (entities.Account, error) {
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"accID":  accID,
+		"status": transition.To,
+	})
+
+	log.Infoln("updating account status")
+
+	acc, err := u.accRepo.UpdateAccountStatus(ctx, accID, transition)
+	if err != nil {
+		return entities.Account{}, err
+	}
+
+	log.Infoln("account status successfully updated")
+
+	return acc, nil
+}
@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"context"
+	"time"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
@@ -11,19 +12,43 @@ import (
 type Repository interface {
 	CreateAccount(ctx context.Context, acc entities.Account) (vos.AccountID, error)
 	GetAccountByID(ctx context.Context, accID vos.AccountID) (entities.Account, error)
-	Deposit(ctx context.Context, accID vos.AccountID, amount vos.Money) error
-	Withdraw(ctx context.Context, accID vos.AccountID, amount vos.Money) error
-	DecreaseAvailableCredit(ctx context.Context, accID vos.AccountID, amount vos.Money) error
+	GetAccountByDocument(ctx context.Context, doc vos.Document) (entities.Account, error)
+	ListAccounts(ctx context.Context, filter entities.AccountFilter) ([]entities.Account, error)
+	UpdateAccountStatus(ctx context.Context, accID vos.AccountID, transition entities.StatusTransition) (entities.Account, error)
+	UpdateCreditLimit(ctx context.Context, change entities.CreditLimitChange) (entities.Account, error)
+	Deposit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error)
+	Withdraw(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error)
+	DecreaseAvailableCredit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error)
+	IncreaseAvailableCredit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error)
+	Transfer(ctx context.Context, key vos.IdempotencyKey, from, to vos.AccountID, amount vos.Money) (debit, credit entities.Entry, err error)
+	GetEntryByIdempotencyKey(ctx context.Context, key vos.IdempotencyKey) (entities.Entry, error)
+	ListEntries(ctx context.Context, accID vos.AccountID, filter entities.StatementFilter) ([]entities.Entry, error)
+	PlaceHold(ctx context.Context, hold entities.Hold) (entities.Hold, entities.BalanceUpdate, error)
+	GetHold(ctx context.Context, holdID vos.HoldID) (entities.Hold, error)
+	GetHoldByIdempotencyKey(ctx context.Context, key vos.IdempotencyKey) (entities.Hold, error)
+	CaptureHold(ctx context.Context, key vos.IdempotencyKey, holdID vos.HoldID, amount vos.Money) (entities.Entry, entities.Hold, error)
+	VoidHold(ctx context.Context, holdID vos.HoldID) (entities.Hold, entities.BalanceUpdate, error)
+	ExpireHolds(ctx context.Context, now time.Time, limit int) ([]entities.BalanceUpdate, error)
+}
+
+// Broker fans balance updates out to whoever is watching an account
+type Broker interface {
+	Publish(update entities.BalanceUpdate)
+	Subscribe(accID vos.AccountID) (updates <-chan entities.BalanceUpdate, unsubscribe func())
 }
 
 // Usecase of accounts
 type Usecase struct {
 	accRepo Repository
+	broker  Broker
+	holdTTL time.Duration
 }
 
-// NewUsecase builds an acc usecase
-func NewUsecase(accRepo Repository) *Usecase {
+// NewUsecase builds an acc usecase, holds placed through it expiring after holdTTL
+func NewUsecase(accRepo Repository, broker Broker, holdTTL time.Duration) *Usecase {
 	return &Usecase{
 		accRepo: accRepo,
+		broker:  broker,
+		holdTTL: holdTTL,
 	}
 }
@@ -0,0 +1,42 @@
+package accounts
+
+import (
+	"context"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WatchAccount retrieves an account along with the updates of its balance from then on.
+// Updates stop, and their channel gets closed, once ctx is done.
+func (u Usecase) WatchAccount(ctx context.Context, accID vos.AccountID) (_ entities.Account, _ <-chan entities.BalanceUpdate, err error) {
+	const operation = "accounts.Usecase.WatchAccount"
+	ctx, end := instrument(ctx, operation, &err)
+	defer end()
+
+	log := logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"accID": accID,
+	})
+
+	log.Infoln("watching account")
+
+	// subscribing first so that no update gets lost between reading the account and watching it
+	updates, unsubscribe := u.broker.Subscribe(accID)
+
+	acc, err := u.accRepo.GetAccountByID(ctx, accID)
+	if err != nil {
+		unsubscribe()
+		return entities.Account{}, nil, domain.Error(operation, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+
+	return acc, updates, nil
+}
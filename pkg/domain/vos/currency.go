@@ -0,0 +1,61 @@
+package vos
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// Currency is an ISO-4217 currency code
+type Currency string
+
+const (
+	CurrencyBRL Currency = "BRL"
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+	CurrencyARS Currency = "ARS"
+	CurrencyMXN Currency = "MXN"
+	CurrencyCLP Currency = "CLP"
+	CurrencyJPY Currency = "JPY"
+	CurrencyKWD Currency = "KWD"
+)
+
+// DefaultCurrency is assumed whenever a currency is omitted
+const DefaultCurrency = CurrencyBRL
+
+// minorUnits tells how many decimal places amounts of each supported currency have
+var minorUnits = map[Currency]int{
+	CurrencyBRL: 2,
+	CurrencyUSD: 2,
+	CurrencyEUR: 2,
+	CurrencyARS: 2,
+	CurrencyMXN: 2,
+	CurrencyCLP: 0,
+	CurrencyJPY: 0,
+	CurrencyKWD: 3,
+}
+
+// NewCurrency normalizes and validates a currency code, falling back to DefaultCurrency when empty
+func NewCurrency(raw string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(raw)))
+	if currency == "" {
+		return DefaultCurrency, nil
+	}
+
+	if _, ok := minorUnits[currency]; !ok {
+		return "", ErrUnknownCurrency
+	}
+
+	return currency, nil
+}
+
+// MinorUnits tells how many decimal places amounts of the currency have, e.g. 2 for cents
+func (c Currency) MinorUnits() int {
+	return minorUnits[c]
+}
+
+// String returns currency as string
+func (c Currency) String() string {
+	return string(c)
+}
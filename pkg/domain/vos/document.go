@@ -1,7 +1,234 @@
 package vos
 
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrDocumentFormat         = errors.New("document has invalid characters")
+	ErrDocumentLength         = errors.New("document must have 11 (CPF) or 14 (CNPJ) characters")
+	ErrDocumentRepeatedDigits = errors.New("document can't have all digits repeated")
+	ErrDocumentCheckDigits    = errors.New("document check digits don't match")
+)
+
+// DocumentType tells which kind of document identifies an account holder
+type DocumentType string
+
+const (
+	DocumentTypeCPF  DocumentType = "cpf"
+	DocumentTypeCNPJ DocumentType = "cnpj"
+
+	// DocumentTypeUnknown is the type of whatever isn't shaped as a CPF nor as a CNPJ
+	DocumentTypeUnknown DocumentType = "unknown"
+)
+
+// String returns document type as string
+func (t DocumentType) String() string {
+	return string(t)
+}
+
+const (
+	cpfLength  = 11
+	cnpjLength = 14
+)
+
+// documentSeparators are the characters punctuating documents
+const documentSeparators = ".-/ "
+
+// documentMasks are how CPFs and CNPJs are punctuated, # standing for each of their characters
+var documentMasks = []string{
+	"###.###.###-##",
+	"##.###.###/####-##",
+}
+
+// Document is a brazilian CPF or CNPJ, always kept without punctuation
 type Document string
 
+// NewDocument normalizes and validates a CPF or CNPJ, punctuated or not
+func NewDocument(raw string) (Document, error) {
+	normalized, ok := normalizeDocument(raw, false)
+	if !ok {
+		return "", ErrDocumentFormat
+	}
+
+	var err error
+	switch len(normalized) {
+	case cpfLength:
+		err = validateCPF(normalized)
+	case cnpjLength:
+		err = validateCNPJ(normalized)
+	default:
+		err = ErrDocumentLength
+		if !isDigits(normalized) {
+			err = ErrDocumentFormat
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return Document(normalized), nil
+}
+
+// NewDocumentPrefix normalizes the leading part of a CPF or CNPJ the same way documents are,
+// so it can be matched against stored ones
+func NewDocumentPrefix(raw string) (string, error) {
+	prefix, ok := normalizeDocument(raw, true)
+	if !ok {
+		return "", ErrDocumentFormat
+	}
+	if len(prefix) > cnpjLength {
+		return "", ErrDocumentLength
+	}
+	for i := range prefix {
+		c := prefix[i]
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return "", ErrDocumentFormat
+		}
+	}
+
+	return prefix, nil
+}
+
+// normalizeDocument drops punctuation and upper cases letters.
+// Punctuation is only accepted where a CPF or CNPJ mask puts it, only the leading part of the mask being matched for prefixes.
+func normalizeDocument(raw string, prefix bool) (string, bool) {
+	doc := strings.ToUpper(strings.TrimSpace(raw))
+	if !strings.ContainsAny(doc, documentSeparators) {
+		return doc, true
+	}
+
+	for _, mask := range documentMasks {
+		if matchesMask(doc, mask, prefix) {
+			return strings.Map(func(r rune) rune {
+				if strings.ContainsRune(documentSeparators, r) {
+					return -1
+				}
+				return r
+			}, doc), true
+		}
+	}
+
+	return "", false
+}
+
+// matchesMask tells whether the document has separators exactly where the mask does and only there
+func matchesMask(doc, mask string, prefix bool) bool {
+	if len(doc) > len(mask) || (!prefix && len(doc) != len(mask)) {
+		return false
+	}
+
+	for i := range doc {
+		if mask[i] == '#' {
+			if strings.IndexByte(documentSeparators, doc[i]) >= 0 {
+				return false
+			}
+			continue
+		}
+		if doc[i] != mask[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Type tells whether the document is a CPF or a CNPJ, DocumentTypeUnknown if shaped as neither
+func (d Document) Type() DocumentType {
+	switch len(d) {
+	case cpfLength:
+		return DocumentTypeCPF
+	case cnpjLength:
+		return DocumentTypeCNPJ
+	default:
+		return DocumentTypeUnknown
+	}
+}
+
+// String returns document as string
 func (d Document) String() string {
 	return string(d)
 }
+
+func validateCPF(doc string) error {
+	if !isDigits(doc) {
+		return ErrDocumentFormat
+	}
+	if strings.Count(doc, doc[:1]) == len(doc) {
+		return ErrDocumentRepeatedDigits
+	}
+
+	first := cpfCheckDigit(doc[:9])
+	second := cpfCheckDigit(doc[:9] + string(first))
+	if doc[9] != first || doc[10] != second {
+		return ErrDocumentCheckDigits
+	}
+
+	return nil
+}
+
+// cpfCheckDigit weights digits from len+1 down to 2
+func cpfCheckDigit(digits string) byte {
+	sum := 0
+	for i := range digits {
+		sum += int(digits[i]-'0') * (len(digits) + 1 - i)
+	}
+
+	rest := sum * 10 % 11
+	if rest == 10 {
+		rest = 0
+	}
+
+	return byte('0' + rest)
+}
+
+// validateCNPJ also accepts alphanumeric CNPJs, whose first 12 characters may be uppercase letters.
+// Check digits are always numeric.
+func validateCNPJ(doc string) error {
+	for i := range doc {
+		c := doc[i]
+		isDigit := c >= '0' && c <= '9'
+		isLetter := c >= 'A' && c <= 'Z'
+		if !isDigit && (!isLetter || i >= 12) {
+			return ErrDocumentFormat
+		}
+	}
+	if strings.Count(doc, doc[:1]) == len(doc) {
+		return ErrDocumentRepeatedDigits
+	}
+
+	first := cnpjCheckDigit(doc[:12])
+	second := cnpjCheckDigit(doc[:12] + string(first))
+	if doc[12] != first || doc[13] != second {
+		return ErrDocumentCheckDigits
+	}
+
+	return nil
+}
+
+// cnpjCheckDigit weights characters cycling from 9 down to 2, right to left.
+// Letters are worth their ASCII code minus 48, the same as digits.
+func cnpjCheckDigit(chars string) byte {
+	sum := 0
+	for i := range chars {
+		weight := 2 + (len(chars)-1-i)%8
+		sum += int(chars[i]-'0') * weight
+	}
+
+	rest := sum % 11
+	if rest < 2 {
+		return '0'
+	}
+
+	return byte('0' + 11 - rest)
+}
+
+func isDigits(s string) bool {
+	for i := range s {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
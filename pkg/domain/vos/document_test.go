@@ -0,0 +1,197 @@
+package vos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewDocument(t *testing.T) {
+	testTable := []struct {
+		Name         string
+		Raw          string
+		ExpectedErr  error
+		Expected     Document
+		ExpectedType DocumentType
+	}{
+		{
+			Name:         "CPF",
+			Raw:          "12345678909",
+			Expected:     "12345678909",
+			ExpectedType: DocumentTypeCPF,
+		},
+		{
+			Name:         "punctuated CPF",
+			Raw:          " 529.982.247-25 ",
+			Expected:     "52998224725",
+			ExpectedType: DocumentTypeCPF,
+		},
+		{
+			Name:        "wrong CPF first check digit",
+			Raw:         "12345678919",
+			ExpectedErr: ErrDocumentCheckDigits,
+		},
+		{
+			Name:        "wrong CPF second check digit",
+			Raw:         "123.456.789-00",
+			ExpectedErr: ErrDocumentCheckDigits,
+		},
+		{
+			Name:        "repeated CPF digits",
+			Raw:         "111.111.111-11",
+			ExpectedErr: ErrDocumentRepeatedDigits,
+		},
+		{
+			Name:        "CPF with letters",
+			Raw:         "1234567890A",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:         "CNPJ",
+			Raw:          "11222333000181",
+			Expected:     "11222333000181",
+			ExpectedType: DocumentTypeCNPJ,
+		},
+		{
+			Name:         "punctuated CNPJ",
+			Raw:          "11.222.333/0001-81",
+			Expected:     "11222333000181",
+			ExpectedType: DocumentTypeCNPJ,
+		},
+		{
+			Name:        "wrong CNPJ check digits",
+			Raw:         "11.222.333/0001-82",
+			ExpectedErr: ErrDocumentCheckDigits,
+		},
+		{
+			Name:        "repeated CNPJ digits",
+			Raw:         "00000000000000",
+			ExpectedErr: ErrDocumentRepeatedDigits,
+		},
+		{
+			Name:         "alphanumeric CNPJ",
+			Raw:          "12.abc.345/01de-35",
+			Expected:     "12ABC34501DE35",
+			ExpectedType: DocumentTypeCNPJ,
+		},
+		{
+			Name:        "wrong alphanumeric CNPJ check digits",
+			Raw:         "12ABC34501DE36",
+			ExpectedErr: ErrDocumentCheckDigits,
+		},
+		{
+			Name:        "letters in the CNPJ check digits",
+			Raw:         "12ABC34501DE3A",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "negative",
+			Raw:         "-12345678909",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "misplaced CPF separators",
+			Raw:         "1234.567.89-09",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "partially punctuated CPF",
+			Raw:         "123.456.78909",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "misplaced CNPJ separators",
+			Raw:         "11.222.333-0001/81",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "inner spaces",
+			Raw:         "123 456 789 09",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "too short",
+			Raw:         "1234567890",
+			ExpectedErr: ErrDocumentLength,
+		},
+		{
+			Name:        "not a number",
+			Raw:         "invalid",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "empty",
+			Raw:         "",
+			ExpectedErr: ErrDocumentLength,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			doc, err := NewDocument(tt.Raw)
+			assert.ErrorIs(t, err, tt.ExpectedErr)
+			assert.Equal(t, tt.Expected, doc)
+			if tt.ExpectedErr == nil {
+				assert.Equal(t, tt.ExpectedType, doc.Type())
+			}
+		})
+	}
+}
+
+func Test_NewDocumentPrefix(t *testing.T) {
+	testTable := []struct {
+		Name        string
+		Raw         string
+		ExpectedErr error
+		Expected    string
+	}{
+		{
+			Name:     "digits",
+			Raw:      "987654",
+			Expected: "987654",
+		},
+		{
+			Name:     "punctuated CPF prefix",
+			Raw:      "987.654.3",
+			Expected: "9876543",
+		},
+		{
+			Name:     "punctuated CNPJ prefix",
+			Raw:      "11.222.333/0",
+			Expected: "112223330",
+		},
+		{
+			Name:     "alphanumeric CNPJ prefix",
+			Raw:      "12.abc",
+			Expected: "12ABC",
+		},
+		{
+			Name:        "negative",
+			Raw:         "-987",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "misplaced separators",
+			Raw:         "9876.54",
+			ExpectedErr: ErrDocumentFormat,
+		},
+		{
+			Name:        "too long",
+			Raw:         "123456789012345",
+			ExpectedErr: ErrDocumentLength,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			prefix, err := NewDocumentPrefix(tt.Raw)
+			assert.ErrorIs(t, err, tt.ExpectedErr)
+			assert.Equal(t, tt.Expected, prefix)
+		})
+	}
+}
+
+func Test_Document_Type(t *testing.T) {
+	assert.Equal(t, DocumentTypeCPF, Document("12345678909").Type())
+	assert.Equal(t, DocumentTypeCNPJ, Document("11222333000181").Type())
+	assert.Equal(t, DocumentTypeUnknown, Document("").Type())
+	assert.Equal(t, DocumentTypeUnknown, Document("123456789").Type())
+}
@@ -1,8 +1,10 @@
 package vos
 
 type (
-	TransactionID string
-	AccountID     string
+	TransactionID  string
+	AccountID      string
+	IdempotencyKey string
+	HoldID         string
 )
 
 // String returns transaction id as string
@@ -14,3 +16,13 @@ func (t TransactionID) String() string {
 func (a AccountID) String() string {
 	return string(a)
 }
+
+// String returns idempotency key as string
+func (k IdempotencyKey) String() string {
+	return string(k)
+}
+
+// String returns hold id as string
+func (h HoldID) String() string {
+	return string(h)
+}
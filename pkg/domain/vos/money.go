@@ -1,26 +1,218 @@
 package vos
 
-import "strconv"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
 
-// Money represents a monetary amount
-type Money int
+var (
+	ErrMoneyOverflow         = errors.New("money amount out of range")
+	ErrMoneyCurrencyMismatch = errors.New("money amounts in different currencies")
+	ErrInvalidMoney          = errors.New("invalid money amount")
+)
 
-// Float64 converts Money to float64
+// Money represents a monetary amount in the minor unit of its currency, e.g. cents of BRL
+// Its arithmetic fails instead of wrapping around: usecases check credits with Add before moving balances,
+// the database refusing as well the ones which would only overflow once concurrent movements are committed.
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// NewMoney builds an amount of the currency out of its minor units
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{
+		amount:   amount,
+		currency: currency,
+	}
+}
+
+// ParseMoney parses a decimal amount in major units of the currency, e.g. "150.25" for 15025 cents
+func ParseMoney(raw string, currency Currency) (Money, error) {
+	units, ok := minorUnits[currency]
+	if !ok {
+		return Money{}, ErrUnknownCurrency
+	}
+
+	digits := strings.TrimSpace(raw)
+	sign := ""
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+
+	whole, fraction := digits, ""
+	if point := strings.Index(digits, "."); point >= 0 {
+		whole, fraction = digits[:point], digits[point+1:]
+		if fraction == "" {
+			return Money{}, ErrInvalidMoney
+		}
+	}
+
+	if whole == "" || !isDigits(whole) || !isDigits(fraction) || len(fraction) > units {
+		return Money{}, ErrInvalidMoney
+	}
+
+	// the amount in minor units is the decimal without its point, once the fraction is padded to the currency scale
+	amount, err := strconv.ParseInt(sign+whole+fraction+strings.Repeat("0", units-len(fraction)), 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return Money{}, ErrMoneyOverflow
+	}
+	if err != nil {
+		return Money{}, ErrInvalidMoney
+	}
+
+	return NewMoney(amount, currency), nil
+}
+
+// Currency returns the currency of the amount
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// Add sums up amounts of the same currency, failing instead of wrapping around
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, ErrMoneyCurrencyMismatch
+	}
+
+	sum := m.amount + other.amount
+	if (other.amount > 0 && sum < m.amount) || (other.amount < 0 && sum > m.amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(sum, m.currency), nil
+}
+
+// Sub subtracts an amount of the same currency, failing instead of wrapping around
+func (m Money) Sub(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, ErrMoneyCurrencyMismatch
+	}
+
+	diff := m.amount - other.amount
+	if (other.amount > 0 && diff > m.amount) || (other.amount < 0 && diff < m.amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(diff, m.currency), nil
+}
+
+// Cmp compares amounts of the same currency, returning -1, 0 or +1 as m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) (int, error) {
+	if m.currency != other.currency {
+		return 0, ErrMoneyCurrencyMismatch
+	}
+
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Sign returns -1, 0 or +1 as the amount is negative, zero or positive
+func (m Money) Sign() int {
+	switch {
+	case m.amount < 0:
+		return -1
+	case m.amount > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Float64 converts Money to float64 in major units, e.g. 150.25 for 15025 cents
 func (m Money) Float64() float64 {
-	return float64(m) / 100
+	return float64(m.amount) / math.Pow10(m.currency.MinorUnits())
 }
 
 // Int converts Money to int
 func (m Money) Int() int {
-	return int(m)
+	return int(m.amount)
 }
 
 // Int64 converts Money to int64
 func (m Money) Int64() int64 {
-	return int64(m)
+	return m.amount
+}
+
+// Decimal formats the amount in major units of its currency, e.g. "150.25" for 15025 cents
+func (m Money) Decimal() string {
+	digits := strconv.FormatInt(m.amount, 10)
+	sign := ""
+	if m.amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	units := m.currency.MinorUnits()
+	if units == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+
+	point := len(digits) - units
+	return sign + digits[:point] + "." + digits[point:]
 }
 
-// String converts Money to string
+// String converts Money to string, e.g. "150.25 BRL"
 func (m Money) String() string {
-	return strconv.FormatInt(m.Int64(), 10)
+	if m.currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.currency.String()
+}
+
+// MarshalJSON encodes the amount in minor units, its currency is carried by a field of its own
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.amount)
+}
+
+// UnmarshalJSON decodes either an integer amount in minor units or a decimal string in major units, e.g. 15025 or "150.25".
+// Decimal strings are parsed in the currency the amount already holds, DefaultCurrency if none.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var raw string
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return ErrInvalidMoney
+		}
+
+		currency := m.currency
+		if currency == "" {
+			currency = DefaultCurrency
+		}
+
+		parsed, err := ParseMoney(raw, currency)
+		if err != nil {
+			return err
+		}
+
+		*m = parsed
+		return nil
+	}
+
+	amount, err := strconv.ParseInt(string(data), 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return ErrMoneyOverflow
+	}
+	if err != nil {
+		return ErrInvalidMoney
+	}
+
+	m.amount = amount
+	return nil
 }
@@ -0,0 +1,412 @@
+package vos
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Money_Add(t *testing.T) {
+	testTable := []struct {
+		Name        string
+		Money       Money
+		Other       Money
+		ExpectedErr error
+		Expected    Money
+	}{
+		{
+			Name:     "sum",
+			Money:    NewMoney(150, CurrencyBRL),
+			Other:    NewMoney(-50, CurrencyBRL),
+			Expected: NewMoney(100, CurrencyBRL),
+		},
+		{
+			Name:     "up to the max",
+			Money:    NewMoney(math.MaxInt64-1, CurrencyBRL),
+			Other:    NewMoney(1, CurrencyBRL),
+			Expected: NewMoney(math.MaxInt64, CurrencyBRL),
+		},
+		{
+			Name:        "past the max",
+			Money:       NewMoney(math.MaxInt64, CurrencyBRL),
+			Other:       NewMoney(1, CurrencyBRL),
+			ExpectedErr: ErrMoneyOverflow,
+		},
+		{
+			Name:     "down to the min",
+			Money:    NewMoney(math.MinInt64+1, CurrencyBRL),
+			Other:    NewMoney(-1, CurrencyBRL),
+			Expected: NewMoney(math.MinInt64, CurrencyBRL),
+		},
+		{
+			Name:        "past the min",
+			Money:       NewMoney(math.MinInt64, CurrencyBRL),
+			Other:       NewMoney(-1, CurrencyBRL),
+			ExpectedErr: ErrMoneyOverflow,
+		},
+		{
+			Name:        "different currencies",
+			Money:       NewMoney(1, CurrencyBRL),
+			Other:       NewMoney(1, CurrencyUSD),
+			ExpectedErr: ErrMoneyCurrencyMismatch,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			sum, err := tt.Money.Add(tt.Other)
+			assert.ErrorIs(t, err, tt.ExpectedErr)
+			assert.Equal(t, tt.Expected, sum)
+		})
+	}
+}
+
+func Test_Money_Sub(t *testing.T) {
+	testTable := []struct {
+		Name        string
+		Money       Money
+		Other       Money
+		ExpectedErr error
+		Expected    Money
+	}{
+		{
+			Name:     "difference",
+			Money:    NewMoney(100, CurrencyBRL),
+			Other:    NewMoney(150, CurrencyBRL),
+			Expected: NewMoney(-50, CurrencyBRL),
+		},
+		{
+			Name:     "down to the min",
+			Money:    NewMoney(math.MinInt64+1, CurrencyBRL),
+			Other:    NewMoney(1, CurrencyBRL),
+			Expected: NewMoney(math.MinInt64, CurrencyBRL),
+		},
+		{
+			Name:        "past the min",
+			Money:       NewMoney(math.MinInt64, CurrencyBRL),
+			Other:       NewMoney(1, CurrencyBRL),
+			ExpectedErr: ErrMoneyOverflow,
+		},
+		{
+			Name:     "up to the max",
+			Money:    NewMoney(math.MaxInt64-1, CurrencyBRL),
+			Other:    NewMoney(-1, CurrencyBRL),
+			Expected: NewMoney(math.MaxInt64, CurrencyBRL),
+		},
+		{
+			Name:        "past the max",
+			Money:       NewMoney(0, CurrencyBRL),
+			Other:       NewMoney(math.MinInt64, CurrencyBRL),
+			ExpectedErr: ErrMoneyOverflow,
+		},
+		{
+			Name:        "different currencies",
+			Money:       NewMoney(1, CurrencyBRL),
+			Other:       NewMoney(1, CurrencyUSD),
+			ExpectedErr: ErrMoneyCurrencyMismatch,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			diff, err := tt.Money.Sub(tt.Other)
+			assert.ErrorIs(t, err, tt.ExpectedErr)
+			assert.Equal(t, tt.Expected, diff)
+		})
+	}
+}
+
+func Test_Money_Cmp(t *testing.T) {
+	testTable := []struct {
+		Name        string
+		Money       Money
+		Other       Money
+		ExpectedErr error
+		Expected    int
+	}{
+		{
+			Name:     "less",
+			Money:    NewMoney(math.MinInt64, CurrencyBRL),
+			Other:    NewMoney(math.MaxInt64, CurrencyBRL),
+			Expected: -1,
+		},
+		{
+			Name:     "equal",
+			Money:    NewMoney(10, CurrencyBRL),
+			Other:    NewMoney(10, CurrencyBRL),
+			Expected: 0,
+		},
+		{
+			Name:     "greater",
+			Money:    NewMoney(11, CurrencyBRL),
+			Other:    NewMoney(10, CurrencyBRL),
+			Expected: 1,
+		},
+		{
+			Name:        "different currencies",
+			Money:       NewMoney(10, CurrencyBRL),
+			Other:       NewMoney(10, CurrencyUSD),
+			ExpectedErr: ErrMoneyCurrencyMismatch,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			cmp, err := tt.Money.Cmp(tt.Other)
+			assert.ErrorIs(t, err, tt.ExpectedErr)
+			assert.Equal(t, tt.Expected, cmp)
+		})
+	}
+}
+
+func Test_ParseMoney(t *testing.T) {
+	testTable := []struct {
+		Name        string
+		Raw         string
+		Currency    Currency
+		ExpectedErr error
+		Expected    Money
+	}{
+		{
+			Name:     "decimal",
+			Raw:      "150.25",
+			Currency: CurrencyBRL,
+			Expected: NewMoney(15025, CurrencyBRL),
+		},
+		{
+			Name:     "fraction shorter than the minor units",
+			Raw:      "150.5",
+			Currency: CurrencyBRL,
+			Expected: NewMoney(15050, CurrencyBRL),
+		},
+		{
+			Name:     "integer",
+			Raw:      " 150 ",
+			Currency: CurrencyBRL,
+			Expected: NewMoney(15000, CurrencyBRL),
+		},
+		{
+			Name:     "positive sign",
+			Raw:      "+0.01",
+			Currency: CurrencyBRL,
+			Expected: NewMoney(1, CurrencyBRL),
+		},
+		{
+			Name:     "negative sign",
+			Raw:      "-0.01",
+			Currency: CurrencyBRL,
+			Expected: NewMoney(-1, CurrencyBRL),
+		},
+		{
+			Name:     "no minor units",
+			Raw:      "1500",
+			Currency: CurrencyJPY,
+			Expected: NewMoney(1500, CurrencyJPY),
+		},
+		{
+			Name:        "fraction of a currency without minor units",
+			Raw:         "1500.5",
+			Currency:    CurrencyJPY,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:     "three minor units",
+			Raw:      "1.234",
+			Currency: CurrencyKWD,
+			Expected: NewMoney(1234, CurrencyKWD),
+		},
+		{
+			Name:        "too many fraction digits",
+			Raw:         "1.001",
+			Currency:    CurrencyBRL,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:        "empty",
+			Raw:         "",
+			Currency:    CurrencyBRL,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:        "sign only",
+			Raw:         "-",
+			Currency:    CurrencyBRL,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:        "double sign",
+			Raw:         "--1",
+			Currency:    CurrencyBRL,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:        "point without fraction",
+			Raw:         "1.",
+			Currency:    CurrencyBRL,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:        "fraction without whole",
+			Raw:         ".5",
+			Currency:    CurrencyBRL,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:        "not a number",
+			Raw:         "1e3",
+			Currency:    CurrencyBRL,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:     "max",
+			Raw:      "92233720368547758.07",
+			Currency: CurrencyBRL,
+			Expected: NewMoney(math.MaxInt64, CurrencyBRL),
+		},
+		{
+			Name:        "past the max",
+			Raw:         "92233720368547758.08",
+			Currency:    CurrencyBRL,
+			ExpectedErr: ErrMoneyOverflow,
+		},
+		{
+			Name:        "unknown currency",
+			Raw:         "1",
+			Currency:    "XYZ",
+			ExpectedErr: ErrUnknownCurrency,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			money, err := ParseMoney(tt.Raw, tt.Currency)
+			assert.ErrorIs(t, err, tt.ExpectedErr)
+			assert.Equal(t, tt.Expected, money)
+		})
+	}
+}
+
+func Test_Money_Decimal(t *testing.T) {
+	testTable := []struct {
+		Name            string
+		Money           Money
+		ExpectedDecimal string
+		ExpectedString  string
+	}{
+		{
+			Name:            "cents",
+			Money:           NewMoney(15025, CurrencyBRL),
+			ExpectedDecimal: "150.25",
+			ExpectedString:  "150.25 BRL",
+		},
+		{
+			Name:            "less than a unit",
+			Money:           NewMoney(5, CurrencyUSD),
+			ExpectedDecimal: "0.05",
+			ExpectedString:  "0.05 USD",
+		},
+		{
+			Name:            "negative",
+			Money:           NewMoney(-5, CurrencyBRL),
+			ExpectedDecimal: "-0.05",
+			ExpectedString:  "-0.05 BRL",
+		},
+		{
+			Name:            "no minor units",
+			Money:           NewMoney(-1500, CurrencyJPY),
+			ExpectedDecimal: "-1500",
+			ExpectedString:  "-1500 JPY",
+		},
+		{
+			Name:            "three minor units",
+			Money:           NewMoney(1234, CurrencyKWD),
+			ExpectedDecimal: "1.234",
+			ExpectedString:  "1.234 KWD",
+		},
+		{
+			Name:            "min",
+			Money:           NewMoney(math.MinInt64, CurrencyBRL),
+			ExpectedDecimal: "-92233720368547758.08",
+			ExpectedString:  "-92233720368547758.08 BRL",
+		},
+		{
+			Name:            "no currency",
+			Money:           NewMoney(1500, ""),
+			ExpectedDecimal: "1500",
+			ExpectedString:  "1500",
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert.Equal(t, tt.ExpectedDecimal, tt.Money.Decimal())
+			assert.Equal(t, tt.ExpectedString, tt.Money.String())
+		})
+	}
+}
+
+func Test_Money_UnmarshalJSON(t *testing.T) {
+	testTable := []struct {
+		Name        string
+		Initial     Money
+		JSON        string
+		ExpectedErr error
+		Expected    Money
+	}{
+		{
+			Name:     "integer in minor units",
+			JSON:     `15025`,
+			Expected: NewMoney(15025, ""),
+		},
+		{
+			Name:     "integer keeps the currency",
+			Initial:  NewMoney(0, CurrencyJPY),
+			JSON:     `1500`,
+			Expected: NewMoney(1500, CurrencyJPY),
+		},
+		{
+			Name:        "integer past the max",
+			JSON:        `9223372036854775808`,
+			ExpectedErr: ErrMoneyOverflow,
+		},
+		{
+			Name:        "fractional number",
+			JSON:        `150.25`,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:     "decimal string in the default currency",
+			JSON:     `"150.25"`,
+			Expected: NewMoney(15025, DefaultCurrency),
+		},
+		{
+			Name:     "decimal string in the currency held",
+			Initial:  NewMoney(0, CurrencyKWD),
+			JSON:     `"1.234"`,
+			Expected: NewMoney(1234, CurrencyKWD),
+		},
+		{
+			Name:        "decimal string with too many fraction digits",
+			JSON:        `"1.001"`,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:        "empty string",
+			JSON:        `""`,
+			ExpectedErr: ErrInvalidMoney,
+		},
+		{
+			Name:     "null",
+			Initial:  NewMoney(10, CurrencyBRL),
+			JSON:     `null`,
+			Expected: NewMoney(10, CurrencyBRL),
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			money := tt.Initial
+			err := json.Unmarshal([]byte(tt.JSON), &money)
+			assert.ErrorIs(t, err, tt.ExpectedErr)
+			if tt.ExpectedErr == nil {
+				assert.Equal(t, tt.Expected, money)
+			}
+		})
+	}
+}
@@ -11,9 +11,10 @@ import (
 
 // CreateAccount creates an account
 // @Summary Creates an account
-// @Description Creates an bank account
+// @Description Creates an bank account holding balances in the credit limit currency, its main one, plus any other listed
 // @Tags Accounts
 // @Param Body body CreateAccountRequest true "Body"
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Success 201 {object} CreateAccountResponse
@@ -21,7 +22,7 @@ import (
 // @Failure 409 "Account already registered"
 // @Failure 422 "Could not create account"
 // @Failure 500 "Internal server error"
-// @Router /accounts [post]
+// @Router /api/v1/accounts [post]
 func (h Handler) CreateAccount(r *http.Request) responses.Response {
 	operation := "accounts.Handler.CreateAccount"
 
@@ -32,7 +33,8 @@ func (h Handler) CreateAccount(r *http.Request) responses.Response {
 		return responses.BadRequest(domain.Error(operation, err), responses.ErrInvalidBody)
 	}
 
-	accID, err := h.Usecase.CreateAccount(ctx, body.Document, body.CreditLimit)
+	creditLimit := vos.NewMoney(body.CreditLimit.Int64(), body.Currency)
+	accID, err := h.Usecase.CreateAccount(ctx, body.Document, creditLimit, body.Currencies...)
 	if err != nil {
 		return responses.ErrorResponse(domain.Error(operation, err))
 	}
@@ -44,8 +46,23 @@ func (h Handler) CreateAccount(r *http.Request) responses.Response {
 
 // CreateAccountRequest payload
 type CreateAccountRequest struct {
-	Document    vos.Document `json:"document_number" example:"12345678900" validate:"required"`
-	CreditLimit vos.Money    `json:"credit_limit" example:"15000"`
+	Document    vos.Document   `json:"document_number" example:"123.456.789-09" validate:"required"`
+	CreditLimit vos.Money      `json:"credit_limit" swaggertype:"integer" example:"15000"`
+	Currency    vos.Currency   `json:"currency,omitempty" example:"BRL"`
+	Currencies  []vos.Currency `json:"currencies,omitempty" swaggertype:"array,string" example:"USD,EUR"`
+}
+
+// UnmarshalJSON decodes the credit limit in the payload currency, which decimal amounts depend on
+func (r *CreateAccountRequest) UnmarshalJSON(data []byte) error {
+	type request CreateAccountRequest
+	body := request{CreditLimit: vos.NewMoney(0, payloadCurrency(data))}
+	err := json.Unmarshal(data, &body)
+	if err != nil {
+		return err
+	}
+
+	*r = CreateAccountRequest(body)
+	return nil
 }
 
 // CreateAccountResponse payload
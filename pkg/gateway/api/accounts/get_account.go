@@ -1,10 +1,12 @@
 package accounts
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/responses"
 	"github.com/google/uuid"
@@ -16,6 +18,7 @@ import (
 // @Description Retrieve an account by its ID
 // @Tags Accounts
 // @Param account_id path string true "Account ID"
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Success 200 {object} GetAccountResponse
@@ -23,7 +26,7 @@ import (
 // @Failure 404 "Account not found"
 // @Failure 422 "Could not create account"
 // @Failure 500 "Internal server error"
-// @Router /accounts/{account_id} [get]
+// @Router /api/v1/accounts/{account_id} [get]
 func (h Handler) GetAccount(r *http.Request) responses.Response {
 	operation := "accounts.Handler.GetAccount"
 
@@ -38,22 +41,92 @@ func (h Handler) GetAccount(r *http.Request) responses.Response {
 		return responses.ErrorResponse(domain.Error(operation, err))
 	}
 
-	return responses.OK(GetAccountResponse{
-		ID:              acc.ID,
-		Document:        acc.Document,
-		Balance:         acc.Balance,
-		AvailableCredit: acc.AvailableCredit,
-		CreatedAt:       acc.CreatedAt,
-		UpdateAt:        acc.UpdateAt,
-	})
+	return responses.OK(newGetAccountResponse(acc))
 }
 
-// GetAccountResponse payload
+var errMissingDocument = errors.New("missing document number")
+
+// GetAccountByDocument gets an account by its owner document
+// @Summary Gets an account by document
+// @Description Retrieve an account by its owner document, either formatted or digits only
+// @Tags Accounts
+// @Param document_number query string true "Owner CPF or CNPJ"
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} GetAccountResponse
+// @Failure 400 "Could not parse request"
+// @Failure 404 "Account not found"
+// @Failure 422 "Invalid document"
+// @Failure 500 "Internal server error"
+// @Router /api/v1/accounts [get]
+func (h Handler) GetAccountByDocument(r *http.Request) responses.Response {
+	operation := "accounts.Handler.GetAccountByDocument"
+
+	ctx := r.Context()
+	doc := r.URL.Query().Get("document_number")
+	if doc == "" {
+		return responses.BadRequest(domain.Error(operation, errMissingDocument), responses.ErrInvalidParams)
+	}
+
+	acc, err := h.Usecase.GetAccountByDocument(ctx, vos.Document(doc))
+	if err != nil {
+		return responses.ErrorResponse(domain.Error(operation, err))
+	}
+
+	return responses.OK(newGetAccountResponse(acc))
+}
+
+// GetAccountResponse payload, amounts at the top level being the ones in the account main currency
 type GetAccountResponse struct {
-	ID              vos.AccountID `json:"account_id"`
-	Document        vos.Document  `json:"document_number"`
-	Balance         vos.Money     `json:"balance"`
-	AvailableCredit vos.Money     `json:"available_credit_limit"`
-	CreatedAt       time.Time     `json:"created_at"`
-	UpdateAt        time.Time     `json:"updated_at"`
+	ID               vos.AccountID     `json:"account_id"`
+	Document         vos.Document      `json:"document_number"`
+	DocumentType     string            `json:"document_type,omitempty" example:"cpf"`
+	Currency         vos.Currency      `json:"currency" example:"BRL"`
+	Balance          vos.Money         `json:"balance" swaggertype:"integer"`
+	AvailableBalance vos.Money         `json:"available_balance" swaggertype:"integer"`
+	CreditLimit      vos.Money         `json:"credit_limit" swaggertype:"integer"`
+	AvailableCredit  vos.Money         `json:"available_credit_limit" swaggertype:"integer"`
+	Balances         []BalanceResponse `json:"balances"`
+	Status           string            `json:"status" example:"active"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdateAt         time.Time         `json:"updated_at"`
+}
+
+// BalanceResponse holds the account amounts in one of its currencies
+type BalanceResponse struct {
+	Currency         vos.Currency `json:"currency" example:"BRL"`
+	Balance          vos.Money    `json:"balance" swaggertype:"integer"`
+	AvailableBalance vos.Money    `json:"available_balance" swaggertype:"integer"`
+	CreditLimit      vos.Money    `json:"credit_limit" swaggertype:"integer"`
+	AvailableCredit  vos.Money    `json:"available_credit_limit" swaggertype:"integer"`
+}
+
+func newGetAccountResponse(acc entities.Account) GetAccountResponse {
+	main := acc.MainBalance()
+	resp := GetAccountResponse{
+		ID:               acc.ID,
+		Document:         acc.Document,
+		DocumentType:     acc.DocumentType.String(),
+		Currency:         acc.Currency,
+		Balance:          main.Balance,
+		AvailableBalance: main.AvailableBalance(),
+		CreditLimit:      main.CreditLimit,
+		AvailableCredit:  main.AvailableCredit,
+		Balances:         make([]BalanceResponse, 0, len(acc.Balances)),
+		Status:           acc.Status.String(),
+		CreatedAt:        acc.CreatedAt,
+		UpdateAt:         acc.UpdateAt,
+	}
+	for _, balance := range acc.Balances {
+		resp.Balances = append(resp.Balances, BalanceResponse{
+			Currency:         balance.Currency(),
+			Balance:          balance.Balance,
+			AvailableBalance: balance.AvailableBalance(),
+			CreditLimit:      balance.CreditLimit,
+			AvailableCredit:  balance.AvailableCredit,
+		})
+	}
+
+	return resp
 }
@@ -0,0 +1,167 @@
+package accounts
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/responses"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetAccountStatement gets an account statement
+// @Summary Gets an account statement
+// @Description Retrieve account movements, newest first, paginated by cursor
+// @Tags Accounts
+// @Param account_id path string true "Account ID"
+// @Param from query string false "Movements since this RFC3339 date (inclusive)"
+// @Param to query string false "Movements until this RFC3339 date (exclusive)"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor of the next page"
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} GetStatementResponse
+// @Failure 400 "Could not parse request"
+// @Failure 404 "Account not found"
+// @Failure 500 "Internal server error"
+// @Router /api/v1/accounts/{account_id}/statement [get]
+func (h Handler) GetAccountStatement(r *http.Request) responses.Response {
+	operation := "accounts.Handler.GetAccountStatement"
+
+	ctx := r.Context()
+	accID, err := uuid.Parse(mux.Vars(r)["account_id"])
+	if err != nil {
+		return responses.BadRequest(domain.Error(operation, err), responses.ErrInvalidAccID)
+	}
+
+	filter, err := parseStatementFilter(r)
+	if err != nil {
+		return responses.BadRequest(domain.Error(operation, err), responses.ErrInvalidParams)
+	}
+
+	statement, err := h.Usecase.GetStatement(ctx, vos.AccountID(accID.String()), filter)
+	if err != nil {
+		return responses.ErrorResponse(domain.Error(operation, err))
+	}
+
+	resp := GetStatementResponse{
+		Entries: make([]StatementEntry, 0, len(statement.Entries)),
+	}
+	for _, entry := range statement.Entries {
+		resp.Entries = append(resp.Entries, StatementEntry{
+			TransactionID:   entry.ID,
+			Operation:       entry.Operation.String(),
+			Currency:        entry.Amount.Currency(),
+			Amount:          entry.Amount,
+			Balance:         entry.Balance,
+			AvailableCredit: entry.AvailableCredit,
+			CounterpartID:   entry.CounterpartID,
+			CreatedAt:       entry.CreatedAt,
+		})
+	}
+	if statement.Next != nil {
+		resp.NextCursor = encodeCursor(*statement.Next)
+	}
+
+	return responses.OK(resp)
+}
+
+// GetStatementResponse payload
+type GetStatementResponse struct {
+	Entries    []StatementEntry `json:"entries"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// StatementEntry is a statement line along with the running balance
+type StatementEntry struct {
+	TransactionID   vos.TransactionID `json:"transaction_id"`
+	Operation       string            `json:"operation" example:"deposit"`
+	Currency        vos.Currency      `json:"currency" example:"BRL"`
+	Amount          vos.Money         `json:"amount" swaggertype:"integer"`
+	Balance         vos.Money         `json:"balance" swaggertype:"integer"`
+	AvailableCredit vos.Money         `json:"available_credit_limit" swaggertype:"integer"`
+	CounterpartID   vos.AccountID     `json:"counterpart_account_id,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+func parseStatementFilter(r *http.Request) (entities.StatementFilter, error) {
+	var (
+		filter entities.StatementFilter
+		err    error
+	)
+
+	query := r.URL.Query()
+	if from := query.Get("from"); from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return entities.StatementFilter{}, err
+		}
+	}
+
+	if to := query.Get("to"); to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return entities.StatementFilter{}, err
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return entities.StatementFilter{}, err
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		filter.Before, err = decodeCursor(cursor)
+		if err != nil {
+			return entities.StatementFilter{}, err
+		}
+	}
+
+	return filter, nil
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor builds an opaque cursor out of the entry creation time and ID
+func encodeCursor(cursor entities.EntryCursor) string {
+	raw := fmt.Sprintf("%d:%s", cursor.CreatedAt.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (entities.EntryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return entities.EntryCursor{}, errInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return entities.EntryCursor{}, errInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return entities.EntryCursor{}, errInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return entities.EntryCursor{}, errInvalidCursor
+	}
+
+	return entities.EntryCursor{
+		CreatedAt: time.Unix(0, nanos),
+		ID:        vos.TransactionID(id.String()),
+	}, nil
+}
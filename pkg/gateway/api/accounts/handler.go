@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
@@ -17,8 +18,15 @@ var _ Usecase = accounts.Usecase{}
 
 // Usecase of accoutns
 type Usecase interface {
-	CreateAccount(ctx context.Context, doc vos.Document, creditLimit vos.Money) (vos.AccountID, error)
+	CreateAccount(ctx context.Context, doc vos.Document, creditLimit vos.Money, currencies ...vos.Currency) (vos.AccountID, error)
 	GetAccountByID(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+	GetAccountByDocument(ctx context.Context, doc vos.Document) (entities.Account, error)
+	GetStatement(ctx context.Context, accID vos.AccountID, filter entities.StatementFilter) (entities.Statement, error)
+	ListAccounts(ctx context.Context, filter entities.AccountFilter) (entities.AccountsPage, error)
+	BlockAccount(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+	UnblockAccount(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+	CloseAccount(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+	UpdateCreditLimit(ctx context.Context, accID vos.AccountID, creditLimit vos.Money, changedBy, reason string) (entities.Account, error)
 }
 
 // Handler handles account relared REST requests
@@ -36,9 +44,53 @@ func NewHandler(public *mux.Router, admin *mux.Router, usecase Usecase) *Handler
 		middleware.Handle(h.CreateAccount)).
 		Methods(http.MethodPost)
 
+	public.Handle("/accounts",
+		middleware.Handle(h.GetAccountByDocument)).
+		Methods(http.MethodGet)
+
 	public.Handle("/accounts/{account_id}",
 		middleware.Handle(h.GetAccount)).
 		Methods(http.MethodGet)
 
+	public.Handle("/accounts/{account_id}/statement",
+		middleware.Handle(h.GetAccountStatement)).
+		Methods(http.MethodGet)
+
+	admin.Handle("/accounts",
+		middleware.Handle(h.ListAccounts)).
+		Methods(http.MethodGet)
+
+	admin.Handle("/accounts/{account_id}/block",
+		middleware.Handle(h.BlockAccount)).
+		Methods(http.MethodPost)
+
+	admin.Handle("/accounts/{account_id}/unblock",
+		middleware.Handle(h.UnblockAccount)).
+		Methods(http.MethodPost)
+
+	admin.Handle("/accounts/{account_id}/close",
+		middleware.Handle(h.CloseAccount)).
+		Methods(http.MethodPost)
+
+	admin.Handle("/accounts/{account_id}/credit-limit",
+		middleware.Handle(h.UpdateCreditLimit)).
+		Methods(http.MethodPut)
+
 	return h
 }
+
+// payloadCurrency reads the currency amounts of a JSON payload are in, falling back to the default one.
+// Malformed payloads and unknown currencies are left to be reported by the payload decoding and the usecases.
+func payloadCurrency(data []byte) vos.Currency {
+	var payload struct {
+		Currency string `json:"currency"`
+	}
+	_ = json.Unmarshal(data, &payload)
+
+	currency, err := vos.NewCurrency(payload.Currency)
+	if err != nil {
+		return vos.DefaultCurrency
+	}
+
+	return currency
+}
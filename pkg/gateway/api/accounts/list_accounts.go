@@ -0,0 +1,191 @@
+package accounts
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/responses"
+	"github.com/google/uuid"
+)
+
+// ListAccounts lists accounts
+// @Summary Lists accounts
+// @Description List accounts matching the filters, paginated by cursor
+// @Tags Admin
+// @Param created_from query string false "Accounts created since this RFC3339 date (inclusive)"
+// @Param created_to query string false "Accounts created until this RFC3339 date (exclusive)"
+// @Param currency query string false "Account main currency, BRL by default if balance bounds are given"
+// @Param min_balance query string false "Minimum main balance (inclusive), an integer in minor units or a decimal in major ones, e.g. 1050 or 10.50"
+// @Param max_balance query string false "Maximum main balance (inclusive), an integer in minor units or a decimal in major ones, e.g. 1050 or 10.50"
+// @Param status query string false "Account status" Enums(active, blocked, closed)
+// @Param document_prefix query string false "Leading part of the owner CPF or CNPJ"
+// @Param sort query string false "Sorting, prefixed by - for descending order (default -created_at), balance meaning the main one, sorted within its currency" Enums(created_at, -created_at, balance, -balance)
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor of the next page"
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} ListAccountsResponse
+// @Failure 400 "Could not parse request"
+// @Failure 500 "Internal server error"
+// @Router /admin/v1/accounts [get]
+func (h Handler) ListAccounts(r *http.Request) responses.Response {
+	operation := "accounts.Handler.ListAccounts"
+
+	ctx := r.Context()
+	filter, err := parseAccountFilter(r)
+	if err != nil {
+		return responses.BadRequest(domain.Error(operation, err), responses.ErrInvalidParams)
+	}
+
+	page, err := h.Usecase.ListAccounts(ctx, filter)
+	if err != nil {
+		return responses.ErrorResponse(domain.Error(operation, err))
+	}
+
+	resp := ListAccountsResponse{
+		Accounts: make([]GetAccountResponse, 0, len(page.Accounts)),
+	}
+	for _, acc := range page.Accounts {
+		resp.Accounts = append(resp.Accounts, newGetAccountResponse(acc))
+	}
+	if page.Next != nil {
+		resp.NextCursor = encodeAccountCursor(*page.Next)
+	}
+
+	return responses.OK(resp)
+}
+
+// ListAccountsResponse payload
+type ListAccountsResponse struct {
+	Accounts   []GetAccountResponse `json:"accounts"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+func parseAccountFilter(r *http.Request) (entities.AccountFilter, error) {
+	var (
+		filter entities.AccountFilter
+		err    error
+	)
+
+	query := r.URL.Query()
+	filter.Currency = vos.Currency(query.Get("currency"))
+	if from := query.Get("created_from"); from != "" {
+		filter.CreatedFrom, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return entities.AccountFilter{}, err
+		}
+	}
+
+	if to := query.Get("created_to"); to != "" {
+		filter.CreatedTo, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return entities.AccountFilter{}, err
+		}
+	}
+
+	if min := query.Get("min_balance"); min != "" {
+		filter.MinBalance, err = parseMoney(min, filter.Currency)
+		if err != nil {
+			return entities.AccountFilter{}, err
+		}
+	}
+
+	if max := query.Get("max_balance"); max != "" {
+		filter.MaxBalance, err = parseMoney(max, filter.Currency)
+		if err != nil {
+			return entities.AccountFilter{}, err
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return entities.AccountFilter{}, err
+		}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		filter.After, err = decodeAccountCursor(cursor)
+		if err != nil {
+			return entities.AccountFilter{}, err
+		}
+	}
+
+	filter.Status = entities.AccountStatus(query.Get("status"))
+	filter.DocumentPrefix = query.Get("document_prefix")
+	filter.Sort = entities.AccountSort(query.Get("sort"))
+
+	return filter, nil
+}
+
+// parseMoney parses integers in minor units and decimals in major units of the currency, BRL if not given, as JSON bodies do
+func parseMoney(raw string, currency vos.Currency) (*vos.Money, error) {
+	if !strings.Contains(raw, ".") {
+		amount, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		money := vos.NewMoney(amount, currency)
+		return &money, nil
+	}
+
+	currency, err := vos.NewCurrency(currency.String())
+	if err != nil {
+		return nil, err
+	}
+
+	money, err := vos.ParseMoney(raw, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &money, nil
+}
+
+// encodeAccountCursor builds an opaque cursor out of the account sortable columns and ID
+func encodeAccountCursor(cursor entities.AccountCursor) string {
+	raw := fmt.Sprintf("%d:%d:%s:%s", cursor.CreatedAt.UnixNano(), cursor.Balance.Int64(), cursor.Balance.Currency(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeAccountCursor(cursor string) (entities.AccountCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return entities.AccountCursor{}, errInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 4)
+	if len(parts) != 4 {
+		return entities.AccountCursor{}, errInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return entities.AccountCursor{}, errInvalidCursor
+	}
+
+	balance, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return entities.AccountCursor{}, errInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[3])
+	if err != nil {
+		return entities.AccountCursor{}, errInvalidCursor
+	}
+
+	return entities.AccountCursor{
+		CreatedAt: time.Unix(0, nanos),
+		Balance:   vos.NewMoney(balance, vos.Currency(parts[2])),
+		ID:        vos.AccountID(id.String()),
+	}, nil
+}
@@ -12,31 +12,87 @@ import (
 
 // AccountsMockUsecase is a mock implementation of Usecase.
 //
-// 	func TestSomethingThatUsesUsecase(t *testing.T) {
+//	func TestSomethingThatUsesUsecase(t *testing.T) {
 //
-// 		// make and configure a mocked Usecase
-// 		mockedUsecase := &AccountsMockUsecase{
-// 			CreateAccountFunc: func(ctx context.Context, doc vos.Document, creditLimit vos.Money) (vos.AccountID, error) {
-// 				panic("mock out the CreateAccount method")
-// 			},
-// 			GetAccountByIDFunc: func(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
-// 				panic("mock out the GetAccountByID method")
-// 			},
-// 		}
+//		// make and configure a mocked Usecase
+//		mockedUsecase := &AccountsMockUsecase{
+//			BlockAccountFunc: func(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+//				panic("mock out the BlockAccount method")
+//			},
+//			CloseAccountFunc: func(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+//				panic("mock out the CloseAccount method")
+//			},
+//			CreateAccountFunc: func(ctx context.Context, doc vos.Document, creditLimit vos.Money, currencies ...vos.Currency) (vos.AccountID, error) {
+//				panic("mock out the CreateAccount method")
+//			},
+//			GetAccountByDocumentFunc: func(ctx context.Context, doc vos.Document) (entities.Account, error) {
+//				panic("mock out the GetAccountByDocument method")
+//			},
+//			GetAccountByIDFunc: func(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+//				panic("mock out the GetAccountByID method")
+//			},
+//			GetStatementFunc: func(ctx context.Context, accID vos.AccountID, filter entities.StatementFilter) (entities.Statement, error) {
+//				panic("mock out the GetStatement method")
+//			},
+//			ListAccountsFunc: func(ctx context.Context, filter entities.AccountFilter) (entities.AccountsPage, error) {
+//				panic("mock out the ListAccounts method")
+//			},
+//			UnblockAccountFunc: func(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+//				panic("mock out the UnblockAccount method")
+//			},
+//			UpdateCreditLimitFunc: func(ctx context.Context, accID vos.AccountID, creditLimit vos.Money, changedBy string, reason string) (entities.Account, error) {
+//				panic("mock out the UpdateCreditLimit method")
+//			},
+//		}
 //
-// 		// use mockedUsecase in code that requires Usecase
-// 		// and then make assertions.
+//		// use mockedUsecase in code that requires Usecase
+//		// and then make assertions.
 //
-// 	}
+//	}
 type AccountsMockUsecase struct {
+	// BlockAccountFunc mocks the BlockAccount method.
+	BlockAccountFunc func(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+
+	// CloseAccountFunc mocks the CloseAccount method.
+	CloseAccountFunc func(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+
 	// CreateAccountFunc mocks the CreateAccount method.
-	CreateAccountFunc func(ctx context.Context, doc vos.Document, creditLimit vos.Money) (vos.AccountID, error)
+	CreateAccountFunc func(ctx context.Context, doc vos.Document, creditLimit vos.Money, currencies ...vos.Currency) (vos.AccountID, error)
+
+	// GetAccountByDocumentFunc mocks the GetAccountByDocument method.
+	GetAccountByDocumentFunc func(ctx context.Context, doc vos.Document) (entities.Account, error)
 
 	// GetAccountByIDFunc mocks the GetAccountByID method.
 	GetAccountByIDFunc func(ctx context.Context, accID vos.AccountID) (entities.Account, error)
 
+	// GetStatementFunc mocks the GetStatement method.
+	GetStatementFunc func(ctx context.Context, accID vos.AccountID, filter entities.StatementFilter) (entities.Statement, error)
+
+	// ListAccountsFunc mocks the ListAccounts method.
+	ListAccountsFunc func(ctx context.Context, filter entities.AccountFilter) (entities.AccountsPage, error)
+
+	// UnblockAccountFunc mocks the UnblockAccount method.
+	UnblockAccountFunc func(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+
+	// UpdateCreditLimitFunc mocks the UpdateCreditLimit method.
+	UpdateCreditLimitFunc func(ctx context.Context, accID vos.AccountID, creditLimit vos.Money, changedBy string, reason string) (entities.Account, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// BlockAccount holds details about calls to the BlockAccount method.
+		BlockAccount []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AccID is the accID argument value.
+			AccID vos.AccountID
+		}
+		// CloseAccount holds details about calls to the CloseAccount method.
+		CloseAccount []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AccID is the accID argument value.
+			AccID vos.AccountID
+		}
 		// CreateAccount holds details about calls to the CreateAccount method.
 		CreateAccount []struct {
 			// Ctx is the ctx argument value.
@@ -45,6 +101,15 @@ type AccountsMockUsecase struct {
 			Doc vos.Document
 			// CreditLimit is the creditLimit argument value.
 			CreditLimit vos.Money
+			// Currencies is the currencies argument value.
+			Currencies []vos.Currency
+		}
+		// GetAccountByDocument holds details about calls to the GetAccountByDocument method.
+		GetAccountByDocument []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Doc is the doc argument value.
+			Doc vos.Document
 		}
 		// GetAccountByID holds details about calls to the GetAccountByID method.
 		GetAccountByID []struct {
@@ -53,21 +118,146 @@ type AccountsMockUsecase struct {
 			// AccID is the accID argument value.
 			AccID vos.AccountID
 		}
+		// GetStatement holds details about calls to the GetStatement method.
+		GetStatement []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AccID is the accID argument value.
+			AccID vos.AccountID
+			// Filter is the filter argument value.
+			Filter entities.StatementFilter
+		}
+		// ListAccounts holds details about calls to the ListAccounts method.
+		ListAccounts []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter entities.AccountFilter
+		}
+		// UnblockAccount holds details about calls to the UnblockAccount method.
+		UnblockAccount []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AccID is the accID argument value.
+			AccID vos.AccountID
+		}
+		// UpdateCreditLimit holds details about calls to the UpdateCreditLimit method.
+		UpdateCreditLimit []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AccID is the accID argument value.
+			AccID vos.AccountID
+			// CreditLimit is the creditLimit argument value.
+			CreditLimit vos.Money
+			// ChangedBy is the changedBy argument value.
+			ChangedBy string
+			// Reason is the reason argument value.
+			Reason string
+		}
+	}
+	lockBlockAccount         sync.RWMutex
+	lockCloseAccount         sync.RWMutex
+	lockCreateAccount        sync.RWMutex
+	lockGetAccountByDocument sync.RWMutex
+	lockGetAccountByID       sync.RWMutex
+	lockGetStatement         sync.RWMutex
+	lockListAccounts         sync.RWMutex
+	lockUnblockAccount       sync.RWMutex
+	lockUpdateCreditLimit    sync.RWMutex
+}
+
+// BlockAccount calls BlockAccountFunc.
+func (mock *AccountsMockUsecase) BlockAccount(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		AccID vos.AccountID
+	}{
+		Ctx:   ctx,
+		AccID: accID,
 	}
-	lockCreateAccount  sync.RWMutex
-	lockGetAccountByID sync.RWMutex
+	mock.lockBlockAccount.Lock()
+	mock.calls.BlockAccount = append(mock.calls.BlockAccount, callInfo)
+	mock.lockBlockAccount.Unlock()
+	if mock.BlockAccountFunc == nil {
+		var (
+			accountOut entities.Account
+			errOut     error
+		)
+		return accountOut, errOut
+	}
+	return mock.BlockAccountFunc(ctx, accID)
+}
+
+// BlockAccountCalls gets all the calls that were made to BlockAccount.
+// Check the length with:
+//
+//	len(mockedUsecase.BlockAccountCalls())
+func (mock *AccountsMockUsecase) BlockAccountCalls() []struct {
+	Ctx   context.Context
+	AccID vos.AccountID
+} {
+	var calls []struct {
+		Ctx   context.Context
+		AccID vos.AccountID
+	}
+	mock.lockBlockAccount.RLock()
+	calls = mock.calls.BlockAccount
+	mock.lockBlockAccount.RUnlock()
+	return calls
+}
+
+// CloseAccount calls CloseAccountFunc.
+func (mock *AccountsMockUsecase) CloseAccount(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		AccID vos.AccountID
+	}{
+		Ctx:   ctx,
+		AccID: accID,
+	}
+	mock.lockCloseAccount.Lock()
+	mock.calls.CloseAccount = append(mock.calls.CloseAccount, callInfo)
+	mock.lockCloseAccount.Unlock()
+	if mock.CloseAccountFunc == nil {
+		var (
+			accountOut entities.Account
+			errOut     error
+		)
+		return accountOut, errOut
+	}
+	return mock.CloseAccountFunc(ctx, accID)
+}
+
+// CloseAccountCalls gets all the calls that were made to CloseAccount.
+// Check the length with:
+//
+//	len(mockedUsecase.CloseAccountCalls())
+func (mock *AccountsMockUsecase) CloseAccountCalls() []struct {
+	Ctx   context.Context
+	AccID vos.AccountID
+} {
+	var calls []struct {
+		Ctx   context.Context
+		AccID vos.AccountID
+	}
+	mock.lockCloseAccount.RLock()
+	calls = mock.calls.CloseAccount
+	mock.lockCloseAccount.RUnlock()
+	return calls
 }
 
 // CreateAccount calls CreateAccountFunc.
-func (mock *AccountsMockUsecase) CreateAccount(ctx context.Context, doc vos.Document, creditLimit vos.Money) (vos.AccountID, error) {
+func (mock *AccountsMockUsecase) CreateAccount(ctx context.Context, doc vos.Document, creditLimit vos.Money, currencies ...vos.Currency) (vos.AccountID, error) {
 	callInfo := struct {
 		Ctx         context.Context
 		Doc         vos.Document
 		CreditLimit vos.Money
+		Currencies  []vos.Currency
 	}{
 		Ctx:         ctx,
 		Doc:         doc,
 		CreditLimit: creditLimit,
+		Currencies:  currencies,
 	}
 	mock.lockCreateAccount.Lock()
 	mock.calls.CreateAccount = append(mock.calls.CreateAccount, callInfo)
@@ -79,21 +269,24 @@ func (mock *AccountsMockUsecase) CreateAccount(ctx context.Context, doc vos.Docu
 		)
 		return accountIDOut, errOut
 	}
-	return mock.CreateAccountFunc(ctx, doc, creditLimit)
+	return mock.CreateAccountFunc(ctx, doc, creditLimit, currencies...)
 }
 
 // CreateAccountCalls gets all the calls that were made to CreateAccount.
 // Check the length with:
-//     len(mockedUsecase.CreateAccountCalls())
+//
+//	len(mockedUsecase.CreateAccountCalls())
 func (mock *AccountsMockUsecase) CreateAccountCalls() []struct {
 	Ctx         context.Context
 	Doc         vos.Document
 	CreditLimit vos.Money
+	Currencies  []vos.Currency
 } {
 	var calls []struct {
 		Ctx         context.Context
 		Doc         vos.Document
 		CreditLimit vos.Money
+		Currencies  []vos.Currency
 	}
 	mock.lockCreateAccount.RLock()
 	calls = mock.calls.CreateAccount
@@ -101,6 +294,46 @@ func (mock *AccountsMockUsecase) CreateAccountCalls() []struct {
 	return calls
 }
 
+// GetAccountByDocument calls GetAccountByDocumentFunc.
+func (mock *AccountsMockUsecase) GetAccountByDocument(ctx context.Context, doc vos.Document) (entities.Account, error) {
+	callInfo := struct {
+		Ctx context.Context
+		Doc vos.Document
+	}{
+		Ctx: ctx,
+		Doc: doc,
+	}
+	mock.lockGetAccountByDocument.Lock()
+	mock.calls.GetAccountByDocument = append(mock.calls.GetAccountByDocument, callInfo)
+	mock.lockGetAccountByDocument.Unlock()
+	if mock.GetAccountByDocumentFunc == nil {
+		var (
+			accountOut entities.Account
+			errOut     error
+		)
+		return accountOut, errOut
+	}
+	return mock.GetAccountByDocumentFunc(ctx, doc)
+}
+
+// GetAccountByDocumentCalls gets all the calls that were made to GetAccountByDocument.
+// Check the length with:
+//
+//	len(mockedUsecase.GetAccountByDocumentCalls())
+func (mock *AccountsMockUsecase) GetAccountByDocumentCalls() []struct {
+	Ctx context.Context
+	Doc vos.Document
+} {
+	var calls []struct {
+		Ctx context.Context
+		Doc vos.Document
+	}
+	mock.lockGetAccountByDocument.RLock()
+	calls = mock.calls.GetAccountByDocument
+	mock.lockGetAccountByDocument.RUnlock()
+	return calls
+}
+
 // GetAccountByID calls GetAccountByIDFunc.
 func (mock *AccountsMockUsecase) GetAccountByID(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
 	callInfo := struct {
@@ -125,7 +358,8 @@ func (mock *AccountsMockUsecase) GetAccountByID(ctx context.Context, accID vos.A
 
 // GetAccountByIDCalls gets all the calls that were made to GetAccountByID.
 // Check the length with:
-//     len(mockedUsecase.GetAccountByIDCalls())
+//
+//	len(mockedUsecase.GetAccountByIDCalls())
 func (mock *AccountsMockUsecase) GetAccountByIDCalls() []struct {
 	Ctx   context.Context
 	AccID vos.AccountID
@@ -139,3 +373,179 @@ func (mock *AccountsMockUsecase) GetAccountByIDCalls() []struct {
 	mock.lockGetAccountByID.RUnlock()
 	return calls
 }
+
+// GetStatement calls GetStatementFunc.
+func (mock *AccountsMockUsecase) GetStatement(ctx context.Context, accID vos.AccountID, filter entities.StatementFilter) (entities.Statement, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		AccID  vos.AccountID
+		Filter entities.StatementFilter
+	}{
+		Ctx:    ctx,
+		AccID:  accID,
+		Filter: filter,
+	}
+	mock.lockGetStatement.Lock()
+	mock.calls.GetStatement = append(mock.calls.GetStatement, callInfo)
+	mock.lockGetStatement.Unlock()
+	if mock.GetStatementFunc == nil {
+		var (
+			statementOut entities.Statement
+			errOut       error
+		)
+		return statementOut, errOut
+	}
+	return mock.GetStatementFunc(ctx, accID, filter)
+}
+
+// GetStatementCalls gets all the calls that were made to GetStatement.
+// Check the length with:
+//
+//	len(mockedUsecase.GetStatementCalls())
+func (mock *AccountsMockUsecase) GetStatementCalls() []struct {
+	Ctx    context.Context
+	AccID  vos.AccountID
+	Filter entities.StatementFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		AccID  vos.AccountID
+		Filter entities.StatementFilter
+	}
+	mock.lockGetStatement.RLock()
+	calls = mock.calls.GetStatement
+	mock.lockGetStatement.RUnlock()
+	return calls
+}
+
+// ListAccounts calls ListAccountsFunc.
+func (mock *AccountsMockUsecase) ListAccounts(ctx context.Context, filter entities.AccountFilter) (entities.AccountsPage, error) {
+	callInfo := struct {
+		Ctx    context.Context
+		Filter entities.AccountFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockListAccounts.Lock()
+	mock.calls.ListAccounts = append(mock.calls.ListAccounts, callInfo)
+	mock.lockListAccounts.Unlock()
+	if mock.ListAccountsFunc == nil {
+		var (
+			accountsPageOut entities.AccountsPage
+			errOut          error
+		)
+		return accountsPageOut, errOut
+	}
+	return mock.ListAccountsFunc(ctx, filter)
+}
+
+// ListAccountsCalls gets all the calls that were made to ListAccounts.
+// Check the length with:
+//
+//	len(mockedUsecase.ListAccountsCalls())
+func (mock *AccountsMockUsecase) ListAccountsCalls() []struct {
+	Ctx    context.Context
+	Filter entities.AccountFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter entities.AccountFilter
+	}
+	mock.lockListAccounts.RLock()
+	calls = mock.calls.ListAccounts
+	mock.lockListAccounts.RUnlock()
+	return calls
+}
+
+// UnblockAccount calls UnblockAccountFunc.
+func (mock *AccountsMockUsecase) UnblockAccount(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		AccID vos.AccountID
+	}{
+		Ctx:   ctx,
+		AccID: accID,
+	}
+	mock.lockUnblockAccount.Lock()
+	mock.calls.UnblockAccount = append(mock.calls.UnblockAccount, callInfo)
+	mock.lockUnblockAccount.Unlock()
+	if mock.UnblockAccountFunc == nil {
+		var (
+			accountOut entities.Account
+			errOut     error
+		)
+		return accountOut, errOut
+	}
+	return mock.UnblockAccountFunc(ctx, accID)
+}
+
+// UnblockAccountCalls gets all the calls that were made to UnblockAccount.
+// Check the length with:
+//
+//	len(mockedUsecase.UnblockAccountCalls())
+func (mock *AccountsMockUsecase) UnblockAccountCalls() []struct {
+	Ctx   context.Context
+	AccID vos.AccountID
+} {
+	var calls []struct {
+		Ctx   context.Context
+		AccID vos.AccountID
+	}
+	mock.lockUnblockAccount.RLock()
+	calls = mock.calls.UnblockAccount
+	mock.lockUnblockAccount.RUnlock()
+	return calls
+}
+
+// UpdateCreditLimit calls UpdateCreditLimitFunc.
+func (mock *AccountsMockUsecase) UpdateCreditLimit(ctx context.Context, accID vos.AccountID, creditLimit vos.Money, changedBy string, reason string) (entities.Account, error) {
+	callInfo := struct {
+		Ctx         context.Context
+		AccID       vos.AccountID
+		CreditLimit vos.Money
+		ChangedBy   string
+		Reason      string
+	}{
+		Ctx:         ctx,
+		AccID:       accID,
+		CreditLimit: creditLimit,
+		ChangedBy:   changedBy,
+		Reason:      reason,
+	}
+	mock.lockUpdateCreditLimit.Lock()
+	mock.calls.UpdateCreditLimit = append(mock.calls.UpdateCreditLimit, callInfo)
+	mock.lockUpdateCreditLimit.Unlock()
+	if mock.UpdateCreditLimitFunc == nil {
+		var (
+			accountOut entities.Account
+			errOut     error
+		)
+		return accountOut, errOut
+	}
+	return mock.UpdateCreditLimitFunc(ctx, accID, creditLimit, changedBy, reason)
+}
+
+// UpdateCreditLimitCalls gets all the calls that were made to UpdateCreditLimit.
+// Check the length with:
+//
+//	len(mockedUsecase.UpdateCreditLimitCalls())
+func (mock *AccountsMockUsecase) UpdateCreditLimitCalls() []struct {
+	Ctx         context.Context
+	AccID       vos.AccountID
+	CreditLimit vos.Money
+	ChangedBy   string
+	Reason      string
+} {
+	var calls []struct {
+		Ctx         context.Context
+		AccID       vos.AccountID
+		CreditLimit vos.Money
+		ChangedBy   string
+		Reason      string
+	}
+	mock.lockUpdateCreditLimit.RLock()
+	calls = mock.calls.UpdateCreditLimit
+	mock.lockUpdateCreditLimit.RUnlock()
+	return calls
+}
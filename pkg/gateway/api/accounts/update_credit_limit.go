@@ -0,0 +1,90 @@
+package accounts
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/responses"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// UpdateCreditLimit changes an account credit limit
+// @Summary Changes an account credit limit
+// @Description Sets a new credit limit on the balance in its currency keeping the already reserved credit, which the new limit can't be lower than.
+// @Description The change is audited as made by the token subject.
+// @Tags Admin
+// @Param account_id path string true "Account ID"
+// @Param Body body UpdateCreditLimitRequest true "Body"
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} GetAccountResponse
+// @Failure 400 "Could not parse request"
+// @Failure 404 "Account not found"
+// @Failure 422 "Could not change credit limit"
+// @Failure 500 "Internal server error"
+// @Router /admin/v1/accounts/{account_id}/credit-limit [put]
+func (h Handler) UpdateCreditLimit(r *http.Request) responses.Response {
+	const operation = "accounts.Handler.UpdateCreditLimit"
+
+	ctx := r.Context()
+	accID, err := uuid.Parse(mux.Vars(r)["account_id"])
+	if err != nil {
+		return responses.BadRequest(domain.Error(operation, err), responses.ErrInvalidAccID)
+	}
+
+	var body UpdateCreditLimitRequest
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil || body.CreditLimit == nil {
+		return responses.BadRequest(domain.Error(operation, err), responses.ErrInvalidBody)
+	}
+
+	// changes are attributed to the authenticated caller, never to someone named by the payload
+	caller, _ := auth.FromCtx(ctx)
+
+	creditLimit := vos.NewMoney(body.CreditLimit.Int64(), body.Currency)
+	acc, err := h.Usecase.UpdateCreditLimit(ctx, vos.AccountID(accID.String()), creditLimit, caller.Subject, body.Reason)
+	if err != nil {
+		return responses.ErrorResponse(domain.Error(operation, err))
+	}
+
+	return responses.OK(newGetAccountResponse(acc))
+}
+
+// UpdateCreditLimitRequest payload
+type UpdateCreditLimitRequest struct {
+	CreditLimit *vos.Money   `json:"credit_limit" swaggertype:"integer" example:"20000" validate:"required"`
+	Currency    vos.Currency `json:"currency,omitempty" example:"BRL"`
+	Reason      string       `json:"reason" example:"customer income increased" validate:"required"`
+}
+
+// UnmarshalJSON decodes the credit limit in the payload currency, which decimal amounts depend on
+func (r *UpdateCreditLimitRequest) UnmarshalJSON(data []byte) error {
+	type request UpdateCreditLimitRequest
+	body := struct {
+		*request
+		CreditLimit json.RawMessage `json:"credit_limit"`
+	}{request: (*request)(r)}
+	err := json.Unmarshal(data, &body)
+	if err != nil {
+		return err
+	}
+
+	r.CreditLimit = nil
+	if body.CreditLimit == nil || string(body.CreditLimit) == "null" {
+		return nil
+	}
+
+	creditLimit := vos.NewMoney(0, payloadCurrency(data))
+	err = json.Unmarshal(body.CreditLimit, &creditLimit)
+	if err != nil {
+		return err
+	}
+
+	r.CreditLimit = &creditLimit
+	return nil
+}
@@ -0,0 +1,82 @@
+package accounts
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/responses"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// BlockAccount blocks an account
+// @Summary Blocks an account
+// @Description Prevents an active account from moving money
+// @Tags Admin
+// @Param account_id path string true "Account ID"
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} GetAccountResponse
+// @Failure 400 "Could not parse request"
+// @Failure 404 "Account not found"
+// @Failure 409 "Account is not active"
+// @Failure 500 "Internal server error"
+// @Router /admin/v1/accounts/{account_id}/block [post]
+func (h Handler) BlockAccount(r *http.Request) responses.Response {
+	return h.updateStatus(r, "accounts.Handler.BlockAccount", h.Usecase.BlockAccount)
+}
+
+// UnblockAccount unblocks an account
+// @Summary Unblocks an account
+// @Description Allows a blocked account to move money again
+// @Tags Admin
+// @Param account_id path string true "Account ID"
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} GetAccountResponse
+// @Failure 400 "Could not parse request"
+// @Failure 404 "Account not found"
+// @Failure 409 "Account is not blocked"
+// @Failure 500 "Internal server error"
+// @Router /admin/v1/accounts/{account_id}/unblock [post]
+func (h Handler) UnblockAccount(r *http.Request) responses.Response {
+	return h.updateStatus(r, "accounts.Handler.UnblockAccount", h.Usecase.UnblockAccount)
+}
+
+// CloseAccount closes an account
+// @Summary Closes an account
+// @Description Closes an active or blocked account for good
+// @Tags Admin
+// @Param account_id path string true "Account ID"
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Success 200 {object} GetAccountResponse
+// @Failure 400 "Could not parse request"
+// @Failure 404 "Account not found"
+// @Failure 409 "Account already closed"
+// @Failure 500 "Internal server error"
+// @Router /admin/v1/accounts/{account_id}/close [post]
+func (h Handler) CloseAccount(r *http.Request) responses.Response {
+	return h.updateStatus(r, "accounts.Handler.CloseAccount", h.Usecase.CloseAccount)
+}
+
+func (h Handler) updateStatus(r *http.Request, operation string, update func(ctx context.Context, accID vos.AccountID) (entities.Account, error)) responses.Response {
+	ctx := r.Context()
+	accID, err := uuid.Parse(mux.Vars(r)["account_id"])
+	if err != nil {
+		return responses.BadRequest(domain.Error(operation, err), responses.ErrInvalidAccID)
+	}
+
+	acc, err := update(ctx, vos.AccountID(accID.String()))
+	if err != nil {
+		return responses.ErrorResponse(domain.Error(operation, err))
+	}
+
+	return responses.OK(newGetAccountResponse(acc))
+}
@@ -6,15 +6,17 @@ import (
 	app "github.com/fernandodr19/mybank-acc/pkg"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/accounts"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/middleware"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	http_swagger "github.com/swaggo/http-swagger"
 	"github.com/urfave/negroni"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 // BuildHandler builds api handler
-func BuildHandler(app *app.App) http.Handler {
+func BuildHandler(app *app.App, verifier *auth.Verifier) http.Handler {
 	r := mux.NewRouter()
 
 	r.PathPrefix("/metrics").Handler(promhttp.Handler()).Methods(http.MethodGet)
@@ -23,6 +25,8 @@ func BuildHandler(app *app.App) http.Handler {
 
 	publicV1 := r.PathPrefix("/api/v1").Subrouter()
 	adminV1 := r.PathPrefix("/admin/v1").Subrouter()
+	publicV1.Use(middleware.Metrics, middleware.TraceRoute, middleware.NewScopeAuthorizer(verifier, auth.ScopeAccounts).AuthorizeRequest)
+	adminV1.Use(middleware.Metrics, middleware.TraceRoute, middleware.NewScopeAuthorizer(verifier, auth.ScopeAdmin).AuthorizeRequest)
 	accounts.NewHandler(publicV1, adminV1, *app.Accounts)
 
 	recovery := negroni.NewRecovery()
@@ -32,5 +36,6 @@ func BuildHandler(app *app.App) http.Handler {
 	n.UseFunc(middleware.AssureRequestID)
 	n.UseHandler(middleware.Cors(r))
 
-	return n
+	// spans cover the whole negroni stack, continuing traces propagated by callers
+	return otelhttp.NewHandler(n, "http.request")
 }
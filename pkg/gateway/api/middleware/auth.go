@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/responses"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+)
+
+var _ Authorizer = ScopeAuthorizer{}
+
+// ScopeAuthorizer only lets through requests bearing a valid token granted with its scope
+type ScopeAuthorizer struct {
+	verifier *auth.Verifier
+	scope    auth.Scope
+}
+
+// NewScopeAuthorizer builds an authorizer requiring the given scope
+func NewScopeAuthorizer(verifier *auth.Verifier, scope auth.Scope) ScopeAuthorizer {
+	return ScopeAuthorizer{
+		verifier: verifier,
+		scope:    scope,
+	}
+}
+
+// AuthorizeRequest checks the bearer token and puts the caller identity on context
+func (a ScopeAuthorizer) AuthorizeRequest(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, denied := a.authorize(r)
+		if denied != nil {
+			Handle(func(*http.Request) responses.Response { return *denied })(w, r)
+			return
+		}
+
+		ctx := auth.ToCtx(r.Context(), id)
+		ctx = logger.ToCtx(ctx, logger.FromCtx(ctx).WithField("caller", id.Subject))
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authorize returns the response to be sent back whenever the request is denied
+func (a ScopeAuthorizer) authorize(r *http.Request) (auth.Identity, *responses.Response) {
+	const operation = "middleware.ScopeAuthorizer.AuthorizeRequest"
+
+	token, err := auth.BearerToken(r.Header.Get("Authorization"))
+	if err != nil {
+		resp := responses.Unauthorized(domain.Error(operation, err), responses.ErrUnauthorized)
+		return auth.Identity{}, &resp
+	}
+
+	id, err := a.verifier.Authorize(token, a.scope)
+	if errors.Is(err, auth.ErrInsufficientScope) {
+		resp := responses.Forbidden(domain.Error(operation, err), responses.ErrForbidden)
+		return auth.Identity{}, &resp
+	}
+	if err != nil {
+		resp := responses.Unauthorized(domain.Error(operation, err), responses.ErrUnauthorized)
+		return auth.Identity{}, &resp
+	}
+
+	return id, nil
+}
@@ -36,11 +36,11 @@ func TrimSlashSuffix(w http.ResponseWriter, r *http.Request, next http.HandlerFu
 
 // AssureRequestID create a request id if none ir proveided and insert a logger with it on context
 func AssureRequestID(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
-	reqID := w.Header().Get(shared.XReqID)
+	reqID := r.Header.Get(shared.XReqID)
 	if reqID == "" {
 		reqID = uuid.NewString()
-		w.Header().Set(shared.XReqID, reqID)
 	}
+	w.Header().Set(shared.XReqID, reqID)
 
 	log := logger.Default().WithField(shared.XReqID, reqID)
 	ctx := logger.ToCtx(r.Context(), log)
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceRoute names the request span, opened by otelhttp for the whole stack, after the matched route template
+func TraceRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace.SpanFromContext(r.Context()).SetName(r.Method + " " + routeTemplate(r))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
 )
 
 // Response represents an API response
@@ -47,42 +48,96 @@ var (
 	ErrInvalidBody         = ErrorPayload{Error: Error{Code: "error:invalid_body", Description: "Invalid body"}}
 	ErrInvalidParams       = ErrorPayload{Error: Error{Code: "error:invalid_parameters", Description: "Invalid query parameters"}}
 	ErrNotImplemented      = ErrorPayload{Error: Error{Code: "error:not_implemented", Description: "Not implemented"}}
+	ErrUnauthorized        = ErrorPayload{Error: Error{Code: "error:unauthorized", Description: "Missing or invalid access token"}}
+	ErrForbidden           = ErrorPayload{Error: Error{Code: "error:forbidden", Description: "Access token lacks the required scope"}}
 )
 
 // accounts
 var (
-	ErrAccountNotFound     = ErrorPayload{Error: Error{Code: "error:account_not_found", Description: "Account not found"}}
-	ErrAccountConflict     = ErrorPayload{Error: Error{Code: "error:account_already_registered", Description: "Account already registered"}}
-	ErrInsufficientBalance = ErrorPayload{Error: Error{Code: "error:insufficient_balance", Description: "Insufficient balance"}}
-	ErrInsufficientCredit  = ErrorPayload{Error: Error{Code: "error:insufficient_credit", Description: "Insufficient credit"}}
-	ErrInvalidAccID        = ErrorPayload{Error: Error{Code: "error:invalid_account_id", Description: "Account id must be a UUIDv4"}}
-	ErrInvalidAmount       = ErrorPayload{Error: Error{Code: "error:invalid_amount", Description: "Amount must be greater than 0"}}
-	ErrInvalidCreditLimit  = ErrorPayload{Error: Error{Code: "error:invalid_credit_limit", Description: "Credit limit must be greater than 0"}}
-	ErrInvalidDocument     = ErrorPayload{Error: Error{Code: "error:invalid_document", Description: "Invalid document"}}
+	ErrAccountNotFound          = ErrorPayload{Error: Error{Code: "error:account_not_found", Description: "Account not found"}}
+	ErrAccountConflict          = ErrorPayload{Error: Error{Code: "error:account_already_registered", Description: "Account already registered"}}
+	ErrAccountNotActive         = ErrorPayload{Error: Error{Code: "error:account_not_active", Description: "Account is blocked or closed"}}
+	ErrInvalidStatusChange      = ErrorPayload{Error: Error{Code: "error:invalid_status_transition", Description: "Account can't move to the requested status"}}
+	ErrInsufficientBalance      = ErrorPayload{Error: Error{Code: "error:insufficient_balance", Description: "Insufficient balance"}}
+	ErrInsufficientCredit       = ErrorPayload{Error: Error{Code: "error:insufficient_credit", Description: "Insufficient credit"}}
+	ErrCreditLimitBelowReserved = ErrorPayload{Error: Error{Code: "error:credit_limit_below_reserved", Description: "Credit limit can't be lower than the reserved credit"}}
+	ErrInvalidCreditLimitChange = ErrorPayload{Error: Error{Code: "error:invalid_credit_limit_change", Description: "Credit limit change must have an author and a reason"}}
+	ErrCreditLimitExceeded      = ErrorPayload{Error: Error{Code: "error:credit_limit_exceeded", Description: "Available credit can't exceed the credit limit"}}
+	ErrSameAccountTransfer      = ErrorPayload{Error: Error{Code: "error:same_account_transfer", Description: "Can't transfer to the same account"}}
+	ErrInvalidAccID             = ErrorPayload{Error: Error{Code: "error:invalid_account_id", Description: "Account id must be a UUIDv4"}}
+	ErrInvalidAmount            = ErrorPayload{Error: Error{Code: "error:invalid_amount", Description: "Amount must be greater than 0"}}
+	ErrInvalidCreditLimit       = ErrorPayload{Error: Error{Code: "error:invalid_credit_limit", Description: "Credit limit must be greater than 0"}}
+	ErrInvalidDocument          = ErrorPayload{Error: Error{Code: "error:invalid_document", Description: "Invalid document"}}
+	ErrDocumentFormat           = ErrorPayload{Error: Error{Code: "error:invalid_document", Description: "Document must have only digits and CPF/CNPJ punctuation"}}
+	ErrDocumentLength           = ErrorPayload{Error: Error{Code: "error:invalid_document", Description: "Document must be a CPF (11 digits) or a CNPJ (14 characters)"}}
+	ErrDocumentRepeated         = ErrorPayload{Error: Error{Code: "error:invalid_document", Description: "Document can't have all digits repeated"}}
+	ErrDocumentCheckDigits      = ErrorPayload{Error: Error{Code: "error:invalid_document", Description: "Document check digits don't match"}}
+	ErrIdempotencyKeyReused     = ErrorPayload{Error: Error{Code: "error:idempotency_key_reused", Description: "Idempotency key already used by a different request"}}
+	ErrInvalidCurrency          = ErrorPayload{Error: Error{Code: "error:invalid_currency", Description: "Currency must be a supported ISO-4217 code"}}
+	ErrCurrencyMismatch         = ErrorPayload{Error: Error{Code: "error:currency_mismatch", Description: "Account holds no balance in the currency"}}
+	ErrAmountOutOfRange         = ErrorPayload{Error: Error{Code: "error:amount_out_of_range", Description: "Amount would take the balance out of range"}}
 )
 
 // ErrorResponse maps response error
 func ErrorResponse(err error) Response {
 	switch {
 	case errors.Is(err, accounts.ErrInvalidDocument):
-		return UnprocessableEntity(err, ErrInvalidDocument)
+		return UnprocessableEntity(err, invalidDocument(err))
 	case errors.Is(err, accounts.ErrInvalidCreditLimit):
 		return UnprocessableEntity(err, ErrInvalidCreditLimit)
+	case errors.Is(err, accounts.ErrCreditLimitBelowReserved):
+		return UnprocessableEntity(err, ErrCreditLimitBelowReserved)
+	case errors.Is(err, accounts.ErrInvalidCreditLimitChange):
+		return BadRequest(err, ErrInvalidCreditLimitChange)
 	case errors.Is(err, accounts.ErrAccountConflict):
 		return Conflict(err, ErrAccountConflict)
 	case errors.Is(err, accounts.ErrAccountNotFound):
 		return NotFound(err, ErrAccountNotFound)
+	case errors.Is(err, accounts.ErrAccountNotActive):
+		return UnprocessableEntity(err, ErrAccountNotActive)
+	case errors.Is(err, accounts.ErrInvalidStatusTransition):
+		return Conflict(err, ErrInvalidStatusChange)
 	case errors.Is(err, accounts.ErrInvalidAmount):
 		return UnprocessableEntity(err, ErrInvalidAmount)
 	case errors.Is(err, accounts.ErrInsufficientBalance):
 		return UnprocessableEntity(err, ErrInsufficientBalance)
 	case errors.Is(err, accounts.ErrInsufficientCredit):
 		return UnprocessableEntity(err, ErrInsufficientCredit)
+	case errors.Is(err, accounts.ErrCreditLimitExceeded):
+		return UnprocessableEntity(err, ErrCreditLimitExceeded)
+	case errors.Is(err, accounts.ErrSameAccountTransfer):
+		return UnprocessableEntity(err, ErrSameAccountTransfer)
+	case errors.Is(err, accounts.ErrInvalidCurrency):
+		return UnprocessableEntity(err, ErrInvalidCurrency)
+	case errors.Is(err, accounts.ErrCurrencyMismatch):
+		return UnprocessableEntity(err, ErrCurrencyMismatch)
+	case errors.Is(err, accounts.ErrAmountOutOfRange):
+		return UnprocessableEntity(err, ErrAmountOutOfRange)
+	case errors.Is(err, accounts.ErrInvalidStatementFilter), errors.Is(err, accounts.ErrInvalidAccountFilter):
+		return BadRequest(err, ErrInvalidParams)
+	case errors.Is(err, accounts.ErrIdempotencyKeyReused):
+		return Conflict(err, ErrIdempotencyKeyReused)
 	default:
 		return InternalServerError(err)
 	}
 }
 
+// invalidDocument details why a document was refused
+func invalidDocument(err error) ErrorPayload {
+	switch {
+	case errors.Is(err, vos.ErrDocumentFormat):
+		return ErrDocumentFormat
+	case errors.Is(err, vos.ErrDocumentLength):
+		return ErrDocumentLength
+	case errors.Is(err, vos.ErrDocumentRepeatedDigits):
+		return ErrDocumentRepeated
+	case errors.Is(err, vos.ErrDocumentCheckDigits):
+		return ErrDocumentCheckDigits
+	default:
+		return ErrInvalidDocument
+	}
+}
+
 // InternalServerError 500
 func InternalServerError(err error) Response {
 	return Response{
@@ -111,6 +166,11 @@ func Unauthorized(err error, payload ErrorPayload) Response {
 	return genericError(http.StatusUnauthorized, err, payload)
 }
 
+// Forbidden 403
+func Forbidden(err error, payload ErrorPayload) Response {
+	return genericError(http.StatusForbidden, err, payload)
+}
+
 // NotFound 404
 func NotFound(err error, payload ErrorPayload) Response {
 	return genericError(http.StatusNotFound, err, payload)
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrMissingToken      = errors.New("missing access token")
+	ErrInvalidToken      = errors.New("invalid access token")
+	ErrInsufficientScope = errors.New("access token lacks required scope")
+)
+
+// Scope grants access to a group of routes
+type Scope string
+
+const (
+	// ScopeAccounts grants access to public account routes and money movements
+	ScopeAccounts Scope = "accounts"
+	// ScopeAdmin grants access to admin routes
+	ScopeAdmin Scope = "accounts:admin"
+)
+
+// Identity is the authenticated caller
+type Identity struct {
+	Subject string
+	Scopes  []Scope
+}
+
+// HasScope tells whether the caller was granted a scope
+func (i Identity) HasScope(scope Scope) bool {
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// BearerToken extracts the token out of an authorization header value
+func BearerToken(header string) (string, error) {
+	const prefix = "bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", ErrMissingToken
+	}
+	return strings.TrimSpace(header[len(prefix):]), nil
+}
+
+type ctxKey string
+
+const identityCtxKey ctxKey = "identity-ctx-key"
+
+// ToCtx returns a new context with the caller identity
+func ToCtx(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityCtxKey, id)
+}
+
+// FromCtx retrieves the caller identity from context (inserted by api middleware and grpc interceptor)
+func FromCtx(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityCtxKey).(Identity)
+	return id, ok
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fernandodr19/mybank-acc/pkg/config"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Claims carried by access tokens. Scopes are space separated as in OAuth 2.0.
+type Claims struct {
+	Scope string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// Verifier validates access tokens against locally configured keys
+type Verifier struct {
+	hmacSecret   []byte
+	rsaPublicKey *rsa.PublicKey
+	issuer       string
+	audience     string
+}
+
+// NewVerifier builds a verifier out of the auth config, at least one key is required
+func NewVerifier(cfg config.Auth) (*Verifier, error) {
+	v := &Verifier{
+		hmacSecret: []byte(cfg.HMACSecret),
+		issuer:     cfg.Issuer,
+		audience:   cfg.Audience,
+	}
+
+	if cfg.RSAPublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.RSAPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading rsa public key: %w", err)
+		}
+
+		v.rsaPublicKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("parsing rsa public key: %w", err)
+		}
+	}
+
+	if len(v.hmacSecret) == 0 && v.rsaPublicKey == nil {
+		return nil, errors.New("no key configured to verify access tokens")
+	}
+
+	return v, nil
+}
+
+// Authorize verifies the token and checks it grants the required scope
+func (v Verifier) Authorize(token string, scope Scope) (Identity, error) {
+	id, err := v.Verify(token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if !id.HasScope(scope) {
+		return id, ErrInsufficientScope
+	}
+
+	return id, nil
+}
+
+// Verify checks token signature and registered claims, returning the caller identity
+func (v Verifier) Verify(token string) (Identity, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, v.key,
+		jwt.WithValidMethods(v.validMethods()),
+	)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	// tokens without expiration would be valid forever
+	if claims.ExpiresAt == nil {
+		return Identity{}, fmt.Errorf("%w: missing expiration", ErrInvalidToken)
+	}
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return Identity{}, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.audience != "" && !claims.VerifyAudience(v.audience, true) {
+		return Identity{}, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	id := Identity{Subject: claims.Subject}
+	for _, s := range strings.Fields(claims.Scope) {
+		id.Scopes = append(id.Scopes, Scope(s))
+	}
+
+	return id, nil
+}
+
+// key picks the configured key matching the token signing method
+func (v Verifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.hmacSecret, nil
+	case *jwt.SigningMethodRSA:
+		return v.rsaPublicKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+// validMethods only allows methods whose key was configured, preventing algorithm confusion
+func (v Verifier) validMethods() []string {
+	var methods []string
+	if len(v.hmacSecret) > 0 {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if v.rsaPublicKey != nil {
+		methods = append(methods, "RS256", "RS384", "RS512")
+	}
+	return methods
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/db/postgres/sqlc"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/tracing"
+	pgx_errors "github.com/jackc/pgx/v4"
+)
+
+// PlaceHold holds funds of the balance in the hold currency as long as its available balance covers them
+func (r AccountsRepository) PlaceHold(ctx context.Context, hold entities.Hold) (_ entities.Hold, _ entities.BalanceUpdate, err error) {
+	const operation = "postgres.AccountsRepository.PlaceHold"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	var update entities.BalanceUpdate
+	currency := hold.Amount.Currency()
+	err = r.inTx(ctx, func(q *sqlc.Queries) error {
+		row, err := q.HoldFunds(ctx, sqlc.HoldFundsParams{
+			AccountID: hold.AccountID.String(),
+			Currency:  currency.String(),
+			Amount:    hold.Amount.Int64(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return diagnoseUpdate(ctx, q, hold.AccountID, currency, accounts.ErrInsufficientBalance)
+		}
+		if err != nil {
+			return err
+		}
+
+		rawHold, err := q.CreateHold(ctx, sqlc.CreateHoldParams{
+			AccountID: hold.AccountID.String(),
+			Currency:  currency.String(),
+			Amount:    hold.Amount.Int64(),
+			IdempotencyKey: sql.NullString{
+				String: hold.IdempotencyKey.String(),
+				Valid:  hold.IdempotencyKey != "",
+			},
+			ExpiresAt: hold.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		hold = mapRawHold(rawHold)
+		update = entities.NewHoldBalanceUpdate(hold, balances(row).balance(currency))
+		return appendEvent(ctx, q, entities.NewHoldEvent(hold))
+	})
+	if err != nil {
+		return entities.Hold{}, entities.BalanceUpdate{}, domain.Error(operation, err)
+	}
+
+	return hold, update, nil
+}
+
+// GetHold retrieves a hold by ID
+func (r AccountsRepository) GetHold(ctx context.Context, holdID vos.HoldID) (_ entities.Hold, err error) {
+	const operation = "postgres.AccountsRepository.GetHold"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	rawHold, err := r.q.GetHold(ctx, holdID.String())
+	if err != nil {
+		if err == pgx_errors.ErrNoRows {
+			return entities.Hold{}, accounts.ErrHoldNotFound
+		}
+		return entities.Hold{}, domain.Error(operation, err)
+	}
+
+	return mapRawHold(rawHold), nil
+}
+
+// GetHoldByIdempotencyKey retrieves the hold placed under an idempotency key
+func (r AccountsRepository) GetHoldByIdempotencyKey(ctx context.Context, key vos.IdempotencyKey) (_ entities.Hold, err error) {
+	const operation = "postgres.AccountsRepository.GetHoldByIdempotencyKey"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	rawHold, err := r.q.GetHoldByIdempotencyKey(ctx, key.String())
+	if err != nil {
+		if err == pgx_errors.ErrNoRows {
+			return entities.Hold{}, accounts.ErrHoldNotFound
+		}
+		return entities.Hold{}, domain.Error(operation, err)
+	}
+
+	return mapRawHold(rawHold), nil
+}
+
+// CaptureHold withdraws the amount from the held funds and releases the rest of them, registering the withdrawal on the ledger.
+// The hold is returned settled along with the entry.
+func (r AccountsRepository) CaptureHold(ctx context.Context, key vos.IdempotencyKey, holdID vos.HoldID, amount vos.Money) (_ entities.Entry, _ entities.Hold, err error) {
+	const operation = "postgres.AccountsRepository.CaptureHold"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	var (
+		rawEntry sqlc.Entry
+		captured entities.Hold
+	)
+	err = r.inTx(ctx, func(q *sqlc.Queries) error {
+		hold, err := lockActiveHold(ctx, q, holdID)
+		if err != nil {
+			return err
+		}
+
+		row, err := q.CaptureHeldFunds(ctx, sqlc.CaptureHeldFundsParams{
+			AccountID:      hold.AccountID.String(),
+			Currency:       hold.Amount.Currency().String(),
+			CapturedAmount: amount.Int64(),
+			HeldAmount:     hold.Amount.Int64(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return diagnoseUpdate(ctx, q, hold.AccountID, hold.Amount.Currency(), accounts.ErrAccountNotActive)
+		}
+		if err != nil {
+			return err
+		}
+
+		rawEntry, err = createEntry(ctx, q, entities.Entry{
+			AccountID:      hold.AccountID,
+			Operation:      entities.OperationWithdrawal,
+			Amount:         amount,
+			IdempotencyKey: key,
+		}, balances(row))
+		if err != nil {
+			return err
+		}
+
+		rawHold, err := q.SettleHold(ctx, sqlc.SettleHoldParams{
+			ID:             holdID.String(),
+			Status:         entities.HoldStatusCaptured.String(),
+			CapturedAmount: amount.Int64(),
+			EntryID:        nullUUID(rawEntry.ID),
+		})
+		if err != nil {
+			return err
+		}
+
+		captured = mapRawHold(rawHold)
+		return appendEvent(ctx, q, entities.NewHoldEvent(captured))
+	})
+	if err != nil {
+		return entities.Entry{}, entities.Hold{}, domain.Error(operation, err)
+	}
+
+	return mapRawEntry(rawEntry), captured, nil
+}
+
+// VoidHold releases the funds of a hold still active and not expired yet
+func (r AccountsRepository) VoidHold(ctx context.Context, holdID vos.HoldID) (_ entities.Hold, _ entities.BalanceUpdate, err error) {
+	const operation = "postgres.AccountsRepository.VoidHold"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	var (
+		hold   entities.Hold
+		update entities.BalanceUpdate
+	)
+	err = r.inTx(ctx, func(q *sqlc.Queries) error {
+		// holds past their expiration are left to the sweeper, so that they always end up expired
+		active, err := lockActiveHold(ctx, q, holdID)
+		if err != nil {
+			return err
+		}
+
+		hold, update, err = releaseHold(ctx, q, active, entities.HoldStatusVoided)
+		return err
+	})
+	if err != nil {
+		return entities.Hold{}, entities.BalanceUpdate{}, domain.Error(operation, err)
+	}
+
+	return hold, update, nil
+}
+
+// ExpireHolds releases the funds of up to limit active holds expired by now, oldest first, returning the balance update of each.
+// Holds being handled by a concurrent transaction are skipped, to be expired by a later call if still active.
+func (r AccountsRepository) ExpireHolds(ctx context.Context, now time.Time, limit int) (_ []entities.BalanceUpdate, err error) {
+	const operation = "postgres.AccountsRepository.ExpireHolds"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	var updates []entities.BalanceUpdate
+	err = r.inTx(ctx, func(q *sqlc.Queries) error {
+		rawHolds, err := q.LockExpiredHolds(ctx, sqlc.LockExpiredHoldsParams{
+			Now:      now,
+			MaxHolds: int32(limit),
+		})
+		if err != nil {
+			return err
+		}
+
+		updates = make([]entities.BalanceUpdate, 0, len(rawHolds))
+		for _, rawHold := range rawHolds {
+			_, update, err := releaseHold(ctx, q, mapRawHold(rawHold), entities.HoldStatusExpired)
+			if err != nil {
+				return err
+			}
+			updates = append(updates, update)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, domain.Error(operation, err)
+	}
+
+	return updates, nil
+}
+
+// lockActiveHold locks a hold making sure it can still be captured or voided
+func lockActiveHold(ctx context.Context, q *sqlc.Queries, holdID vos.HoldID) (entities.Hold, error) {
+	rawHold, err := q.LockHold(ctx, holdID.String())
+	if err == pgx_errors.ErrNoRows {
+		return entities.Hold{}, accounts.ErrHoldNotFound
+	}
+	if err != nil {
+		return entities.Hold{}, err
+	}
+
+	hold := mapRawHold(rawHold)
+	if !hold.IsActive(time.Now()) {
+		return entities.Hold{}, accounts.ErrHoldNotActive
+	}
+
+	return hold, nil
+}
+
+// releaseHold gives the held funds back to the available balance, settling the hold with the status.
+// The hold must be locked by the ongoing transaction. Blocked or closed accounts get their funds back as well,
+// so the account is locked without checking its status.
+func releaseHold(ctx context.Context, q *sqlc.Queries, hold entities.Hold, status entities.HoldStatus) (entities.Hold, entities.BalanceUpdate, error) {
+	_, err := q.LockAccount(ctx, hold.AccountID.String())
+	if err != nil {
+		return entities.Hold{}, entities.BalanceUpdate{}, err
+	}
+
+	currency := hold.Amount.Currency()
+	row, err := q.ReleaseHeldFunds(ctx, sqlc.ReleaseHeldFundsParams{
+		AccountID: hold.AccountID.String(),
+		Currency:  currency.String(),
+		Amount:    hold.Amount.Int64(),
+	})
+	if err != nil {
+		return entities.Hold{}, entities.BalanceUpdate{}, err
+	}
+
+	rawHold, err := q.SettleHold(ctx, sqlc.SettleHoldParams{
+		ID:     hold.ID.String(),
+		Status: status.String(),
+	})
+	if err != nil {
+		return entities.Hold{}, entities.BalanceUpdate{}, err
+	}
+
+	hold = mapRawHold(rawHold)
+	update := entities.NewHoldBalanceUpdate(hold, balances(row).balance(currency))
+	return hold, update, appendEvent(ctx, q, entities.NewHoldEvent(hold))
+}
+
+func mapRawHold(rawHold sqlc.Hold) entities.Hold {
+	currency := vos.Currency(rawHold.Currency)
+	hold := entities.Hold{
+		ID:             vos.HoldID(rawHold.ID),
+		AccountID:      vos.AccountID(rawHold.AccountID),
+		Amount:         vos.NewMoney(rawHold.Amount, currency),
+		CapturedAmount: vos.NewMoney(rawHold.CapturedAmount, currency),
+		Status:         entities.HoldStatus(rawHold.Status),
+		IdempotencyKey: vos.IdempotencyKey(rawHold.IdempotencyKey.String),
+		ExpiresAt:      rawHold.ExpiresAt,
+		CreatedAt:      rawHold.CreatedAt,
+		UpdatedAt:      rawHold.UpdatedAt,
+	}
+	if rawHold.EntryID.Valid {
+		hold.EntryID = vos.TransactionID(rawHold.EntryID.UUID.String())
+	}
+
+	return hold
+}
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE entries;
+
+DROP FUNCTION trigger_forbid_changes;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE entries
+(
+    id               UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id       UUID NOT NULL REFERENCES accounts (id),
+    operation        text NOT NULL,
+    amount           bigint NOT NULL CHECK (amount > 0),
+    balance          bigint NOT NULL,
+    available_credit bigint NOT NULL,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX entries_account_id_created_at_idx ON entries (account_id, created_at);
+
+CREATE OR REPLACE FUNCTION trigger_forbid_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'table % is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER forbid_changes_entries
+BEFORE UPDATE OR DELETE ON entries
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_forbid_changes();
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE entries DROP COLUMN idempotency_key;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE entries ADD COLUMN idempotency_key text UNIQUE;
+
+COMMIT;
\ No newline at end of file
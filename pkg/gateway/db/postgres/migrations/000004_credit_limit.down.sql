@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE accounts DROP CONSTRAINT accounts_available_credit_check;
+
+ALTER TABLE accounts DROP COLUMN credit_limit;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE accounts ADD COLUMN credit_limit bigint NOT NULL DEFAULT 0;
+
+-- backfilling doesn't count as an account update
+ALTER TABLE accounts DISABLE TRIGGER set_timestamp_accounts;
+
+-- the limit is at least the credit still available plus the outstanding usage, i.e. reservations not released yet.
+-- Usage is taken from the ledger, so reservations made before it existed are assumed to be released already:
+-- their accounts get the lowest limit consistent with what is known and may need raising through an audited change.
+UPDATE accounts a
+SET credit_limit = a.available_credit + GREATEST(COALESCE((
+    SELECT sum(CASE e.operation WHEN 'credit_reservation' THEN e.amount ELSE -e.amount END) FROM entries e
+    WHERE e.account_id = a.id AND e.operation IN ('credit_reservation', 'credit_release')
+), 0), 0);
+
+ALTER TABLE accounts ENABLE TRIGGER set_timestamp_accounts;
+
+ALTER TABLE accounts ADD CONSTRAINT accounts_available_credit_check CHECK (available_credit <= credit_limit);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE entries DROP COLUMN counterpart_id;
+
+COMMIT;
\ No newline at end of file
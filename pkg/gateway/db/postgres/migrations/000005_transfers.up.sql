@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE entries ADD COLUMN counterpart_id UUID REFERENCES accounts (id);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE accounts DROP CONSTRAINT accounts_balance_check;
+ALTER TABLE accounts DROP CONSTRAINT accounts_available_credit_non_negative_check;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- NOT VALID keeps legacy rows untouched while enforcing the constraints from now on
+ALTER TABLE accounts ADD CONSTRAINT accounts_balance_check CHECK (balance >= 0) NOT VALID;
+ALTER TABLE accounts ADD CONSTRAINT accounts_available_credit_non_negative_check CHECK (available_credit >= 0) NOT VALID;
+
+COMMIT;
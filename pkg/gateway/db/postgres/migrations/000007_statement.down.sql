@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX entries_account_id_created_at_id_idx;
+CREATE INDEX entries_account_id_created_at_idx ON entries (account_id, created_at);
+
+ALTER TABLE entries ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- entries of an account get timestamped in the very order their row locks were granted
+ALTER TABLE entries ALTER COLUMN created_at SET DEFAULT clock_timestamp();
+
+DROP INDEX entries_account_id_created_at_idx;
+CREATE INDEX entries_account_id_created_at_id_idx ON entries (account_id, created_at DESC, id DESC);
+
+COMMIT;
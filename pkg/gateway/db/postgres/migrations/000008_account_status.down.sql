@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE accounts DROP COLUMN status;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE accounts ADD COLUMN status text NOT NULL DEFAULT 'active'
+    CONSTRAINT accounts_status_check CHECK (status IN ('active', 'blocked', 'closed'));
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE credit_limit_changes;
+
+COMMIT;
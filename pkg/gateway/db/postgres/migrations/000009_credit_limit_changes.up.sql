@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE credit_limit_changes
+(
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id     UUID NOT NULL REFERENCES accounts (id),
+    previous_limit bigint NOT NULL,
+    new_limit      bigint NOT NULL,
+    changed_by     text NOT NULL,
+    reason         text NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX credit_limit_changes_account_id_created_at_idx ON credit_limit_changes (account_id, created_at);
+
+CREATE TRIGGER forbid_changes_credit_limit_changes
+BEFORE UPDATE OR DELETE ON credit_limit_changes
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_forbid_changes();
+
+COMMIT;
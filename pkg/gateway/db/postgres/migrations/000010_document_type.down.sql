@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE accounts DROP COLUMN document_type;
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE accounts ADD COLUMN document_type text
+    CONSTRAINT accounts_document_type_check CHECK (document_type IN ('cpf', 'cnpj'));
+
+-- backfilling doesn't count as an account update
+ALTER TABLE accounts DISABLE TRIGGER set_timestamp_accounts;
+
+-- documents registered before validation was in place are left untyped
+-- unless they are shaped like one
+UPDATE accounts SET document_type = 'cpf' WHERE document ~ '^[0-9]{11}$';
+UPDATE accounts SET document_type = 'cnpj' WHERE document ~ '^[0-9]{14}$';
+
+ALTER TABLE accounts ENABLE TRIGGER set_timestamp_accounts;
+
+COMMIT;
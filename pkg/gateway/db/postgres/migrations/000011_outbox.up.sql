@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Events are written along with the state change they describe and relayed to downstream services afterwards
+CREATE TABLE outbox
+(
+    id           bigserial PRIMARY KEY,
+    event_id     UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    account_id   UUID NOT NULL REFERENCES accounts (id),
+    event_type   text NOT NULL,
+    payload      jsonb NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT clock_timestamp(),
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE published_at IS NULL;
+
+COMMIT;
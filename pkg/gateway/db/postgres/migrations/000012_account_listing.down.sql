@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX accounts_document_pattern_idx;
+DROP INDEX accounts_balance_id_idx;
+DROP INDEX accounts_created_at_id_idx;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- keyset pagination of account listings, one index per sortable column
+CREATE INDEX accounts_created_at_id_idx ON accounts (created_at, id);
+CREATE INDEX accounts_balance_id_idx ON accounts (balance, id);
+
+-- lets document prefix searches (LIKE '123%') use an index regardless of collation
+CREATE INDEX accounts_document_pattern_idx ON accounts (document text_pattern_ops);
+
+COMMIT;
@@ -0,0 +1,34 @@
+BEGIN;
+
+ALTER TABLE credit_limit_changes DROP COLUMN currency;
+ALTER TABLE entries DROP COLUMN currency;
+
+ALTER TABLE accounts
+    ADD COLUMN balance bigint NOT NULL DEFAULT 0,
+    ADD COLUMN credit_limit bigint NOT NULL DEFAULT 0,
+    ADD COLUMN available_credit bigint NOT NULL DEFAULT 0;
+
+-- restoring doesn't count as an account update
+ALTER TABLE accounts DISABLE TRIGGER set_timestamp_accounts;
+
+-- only balances in the account currency can be kept
+UPDATE accounts a
+SET balance = b.balance,
+    credit_limit = b.credit_limit,
+    available_credit = b.available_credit
+FROM balances b
+WHERE b.account_id = a.id AND b.currency = a.currency;
+
+ALTER TABLE accounts ENABLE TRIGGER set_timestamp_accounts;
+
+ALTER TABLE accounts ADD CONSTRAINT accounts_available_credit_check CHECK (available_credit <= credit_limit);
+ALTER TABLE accounts ADD CONSTRAINT accounts_balance_check CHECK (balance >= 0) NOT VALID;
+ALTER TABLE accounts ADD CONSTRAINT accounts_available_credit_non_negative_check CHECK (available_credit >= 0) NOT VALID;
+CREATE INDEX accounts_balance_id_idx ON accounts (balance, id);
+
+DROP INDEX balances_currency_balance_idx;
+DROP TABLE balances;
+
+ALTER TABLE accounts DROP COLUMN currency;
+
+COMMIT;
@@ -0,0 +1,48 @@
+BEGIN;
+
+-- every account existing so far was opened in brazilian reais
+ALTER TABLE accounts ADD COLUMN currency text NOT NULL DEFAULT 'BRL';
+ALTER TABLE accounts ALTER COLUMN currency DROP DEFAULT;
+
+-- amounts are kept apart per currency, each balance with its own credit line
+CREATE TABLE balances
+(
+    account_id       UUID NOT NULL REFERENCES accounts (id),
+    currency         text NOT NULL,
+    balance          bigint NOT NULL DEFAULT 0,
+    credit_limit     bigint NOT NULL DEFAULT 0,
+    available_credit bigint NOT NULL DEFAULT 0,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, currency),
+    CONSTRAINT balances_available_credit_check CHECK (available_credit <= credit_limit)
+);
+
+INSERT INTO balances (account_id, currency, balance, credit_limit, available_credit, created_at, updated_at)
+SELECT id, currency, balance, credit_limit, available_credit, created_at, updated_at FROM accounts;
+
+-- NOT VALID keeps legacy rows untouched while enforcing the constraints from now on
+ALTER TABLE balances ADD CONSTRAINT balances_balance_check CHECK (balance >= 0) NOT VALID;
+ALTER TABLE balances ADD CONSTRAINT balances_available_credit_non_negative_check CHECK (available_credit >= 0) NOT VALID;
+
+CREATE TRIGGER set_timestamp_balances
+BEFORE UPDATE ON balances
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
+
+-- account listings are sorted by the balance in the account currency, taking over
+-- from accounts_balance_id_idx which goes away along with accounts.balance
+CREATE INDEX balances_currency_balance_idx ON balances (currency, balance, account_id);
+
+ALTER TABLE accounts
+    DROP COLUMN balance,
+    DROP COLUMN credit_limit,
+    DROP COLUMN available_credit;
+
+ALTER TABLE entries ADD COLUMN currency text NOT NULL DEFAULT 'BRL';
+ALTER TABLE entries ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE credit_limit_changes ADD COLUMN currency text NOT NULL DEFAULT 'BRL';
+ALTER TABLE credit_limit_changes ALTER COLUMN currency DROP DEFAULT;
+
+COMMIT;
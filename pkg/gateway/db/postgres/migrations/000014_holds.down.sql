@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE holds;
+
+ALTER TABLE entries DROP COLUMN held;
+
+ALTER TABLE balances DROP COLUMN held;
+
+COMMIT;
@@ -0,0 +1,37 @@
+BEGIN;
+
+-- held funds are kept out of the available balance until their hold is captured, voided or expires
+ALTER TABLE balances ADD COLUMN held bigint NOT NULL DEFAULT 0;
+ALTER TABLE balances ADD CONSTRAINT balances_held_check CHECK (held >= 0);
+-- legacy balances may be negative, they just can't get funds held
+ALTER TABLE balances ADD CONSTRAINT balances_held_covered_check CHECK (held = 0 OR held <= balance);
+
+-- entries keep what was held right after them, so that the available balance can be told as well
+ALTER TABLE entries ADD COLUMN held bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE holds
+(
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id      UUID NOT NULL,
+    currency        text NOT NULL,
+    amount          bigint NOT NULL CHECK (amount > 0),
+    captured_amount bigint NOT NULL DEFAULT 0,
+    status          text NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'captured', 'voided', 'expired')),
+    idempotency_key text UNIQUE,
+    entry_id        UUID REFERENCES entries (id),
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id, currency) REFERENCES balances (account_id, currency),
+    CONSTRAINT holds_captured_amount_check CHECK (captured_amount >= 0 AND captured_amount <= amount)
+);
+
+CREATE TRIGGER set_timestamp_holds
+BEFORE UPDATE ON holds
+FOR EACH ROW
+EXECUTE PROCEDURE trigger_set_timestamp();
+
+-- the sweeper looks for active holds past their expiration
+CREATE INDEX holds_active_expires_at_idx ON holds (expires_at) WHERE status = 'active';
+
+COMMIT;
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/db/postgres/sqlc"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/tracing"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// outboxLockKey identifies the advisory lock held while relaying events.
+// A single relay runs at a time across instances, otherwise events of an account could be published out of order.
+const outboxLockKey = 0x6f7574626f78
+
+// OutboxRepository reads events written along with account changes
+type OutboxRepository struct {
+	conn *pgxpool.Pool
+}
+
+// NewOutboxRepository returns an outbox repository
+func NewOutboxRepository(conn *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{
+		conn: conn,
+	}
+}
+
+// RelayEvents hands up to limit pending events to publish, oldest first, marking them as published.
+// Relaying stops at the first event publish fails on so that it is retried before any later one.
+// It returns how many events got published, which is 0 whenever another relay is already running.
+func (r OutboxRepository) RelayEvents(ctx context.Context, limit int, publish func(context.Context, entities.Event) error) (_ int, err error) {
+	const operation = "postgres.OutboxRepository.RelayEvents"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	var published []int64
+	var publishErr error
+	err = r.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		q := sqlc.New(tracedDB{tx})
+
+		locked, err := q.LockOutbox(ctx, outboxLockKey)
+		if err != nil || !locked {
+			return err
+		}
+
+		rawEvents, err := q.ListPendingEvents(ctx, int32(limit))
+		if err != nil {
+			return err
+		}
+
+		for _, rawEvent := range rawEvents {
+			publishErr = publish(ctx, mapRawEvent(rawEvent))
+			if publishErr != nil {
+				break
+			}
+			published = append(published, rawEvent.ID)
+		}
+
+		if len(published) == 0 {
+			return nil
+		}
+		return q.MarkEventsPublished(ctx, published)
+	})
+	if err != nil {
+		return 0, domain.Error(operation, err)
+	}
+	if publishErr != nil {
+		return len(published), domain.Error(operation, publishErr)
+	}
+
+	return len(published), nil
+}
+
+// appendEvent writes an event to the outbox, it must run within the transaction of the change it describes
+// and which holds the lock on the account, otherwise its events may be published out of order
+func appendEvent(ctx context.Context, q *sqlc.Queries, event entities.Event) error {
+	payload, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	return q.CreateOutboxEvent(ctx, sqlc.CreateOutboxEventParams{
+		AccountID: event.AccountID.String(),
+		EventType: event.Type.String(),
+		Payload: pgtype.JSONB{
+			Bytes:  payload,
+			Status: pgtype.Present,
+		},
+	})
+}
+
+func mapRawEvent(rawEvent sqlc.Outbox) entities.Event {
+	return entities.Event{
+		ID:        rawEvent.EventID,
+		AccountID: vos.AccountID(rawEvent.AccountID),
+		Type:      entities.EventType(rawEvent.EventType),
+		Data:      json.RawMessage(rawEvent.Payload.Bytes),
+		CreatedAt: rawEvent.CreatedAt,
+	}
+}
@@ -10,23 +10,34 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres" // needed to describe db driver
 	"github.com/golang-migrate/migrate/v4/source/httpfs"
-	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 )
 
-// NewConnection sets up a new connection with migrations
-func NewConnection(ctx context.Context, cfg config.Postgres) (*pgx.Conn, error) {
-	// Maybe use connection pool later on..
-	conn, err := pgx.Connect(ctx, cfg.URL())
+// NewConnection sets up a new connection pool with migrations
+func NewConnection(ctx context.Context, cfg config.Postgres) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	poolCfg.MaxConns = cfg.MaxConns
+	poolCfg.MinConns = cfg.MinConns
+	poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+
+	pool, err := pgxpool.ConnectConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, err
 	}
 
 	err = runMigrations(cfg.URL())
 	if err != nil {
+		pool.Close()
 		return nil, err
 	}
 
-	return conn, nil
+	return pool, nil
 }
 
 //go:embed migrations
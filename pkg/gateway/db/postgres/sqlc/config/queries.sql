@@ -1,23 +1,236 @@
 -- name: CreateAccount :one
-INSERT INTO accounts (document, balance, available_credit)
-VALUES (@document, @balance, @available_credit)
+INSERT INTO accounts (document, document_type, currency)
+VALUES (@document, @document_type, @currency)
 RETURNING id;
 
+-- name: CreateBalance :exec
+INSERT INTO balances (account_id, currency, balance, credit_limit, available_credit)
+VALUES (@account_id, @currency, @balance, @credit_limit, @available_credit);
+
 -- name: GetAccountByID :one
 SELECT * FROM accounts
 WHERE id = @id;
 
--- name: Deposit :execrows
-UPDATE accounts
+-- name: GetAccountByDocument :one
+SELECT * FROM accounts
+WHERE document = @document;
+
+-- name: ListBalances :many
+SELECT * FROM balances
+WHERE account_id = ANY(@account_ids::uuid[])
+ORDER BY account_id, currency;
+
+-- name: GetBalance :one
+SELECT * FROM balances
+WHERE account_id = @account_id AND currency = @currency;
+
+-- name: Deposit :one
+-- locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+UPDATE balances
 SET balance = balance + @amount
-WHERE id = @id;
+WHERE balances.account_id = @account_id AND balances.currency = @currency
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = @account_id AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held;
 
--- name: Withdraw :execrows
-UPDATE accounts
+-- name: Withdraw :one
+-- locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+UPDATE balances
 SET balance = balance - @amount
-WHERE id = @id AND (balance >= @amount);
+WHERE balances.account_id = @account_id AND balances.currency = @currency AND (balance - held >= @amount)
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = @account_id AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held;
 
--- name: DecreaseAvailableCredit :execrows
-UPDATE accounts
+-- name: DecreaseAvailableCredit :one
+-- locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+UPDATE balances
 SET available_credit = available_credit - @amount
-WHERE id = @id;
\ No newline at end of file
+WHERE balances.account_id = @account_id AND balances.currency = @currency AND (available_credit >= @amount)
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = @account_id AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held;
+
+-- name: IncreaseAvailableCredit :one
+-- locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+UPDATE balances
+SET available_credit = available_credit + @amount
+WHERE balances.account_id = @account_id AND balances.currency = @currency AND (available_credit + @amount <= credit_limit)
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = @account_id AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held;
+
+-- name: HoldFunds :one
+-- locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+UPDATE balances
+SET held = held + @amount
+WHERE balances.account_id = @account_id AND balances.currency = @currency AND (balance - held >= @amount)
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = @account_id AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held;
+
+-- name: CaptureHeldFunds :one
+-- locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+UPDATE balances
+SET balance = balance - @captured_amount,
+    held = held - @held_amount
+WHERE balances.account_id = @account_id AND balances.currency = @currency
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = @account_id AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held;
+
+-- name: ReleaseHeldFunds :one
+-- voids and expiries only give held funds back, which accounts get whatever their status,
+-- releaseHold locking the account FOR UPDATE beforehand as every other movement does
+UPDATE balances
+SET held = held - @amount
+WHERE account_id = @account_id AND currency = @currency
+RETURNING balance, available_credit, held;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = @status
+WHERE id = @id AND status = ANY(@from_statuses::text[])
+RETURNING *;
+
+-- name: LockAccount :one
+SELECT * FROM accounts
+WHERE id = @id
+FOR UPDATE;
+
+-- name: LockBalance :one
+SELECT * FROM balances
+WHERE account_id = @account_id AND currency = @currency
+FOR UPDATE;
+
+-- name: UpdateCreditLimit :exec
+UPDATE balances
+SET credit_limit = @credit_limit,
+    available_credit = @available_credit
+WHERE account_id = @account_id AND currency = @currency;
+
+-- name: CreateCreditLimitChange :one
+INSERT INTO credit_limit_changes (account_id, currency, previous_limit, new_limit, changed_by, reason)
+VALUES (@account_id, @currency, @previous_limit, @new_limit, @changed_by, @reason)
+RETURNING *;
+
+-- name: LockAccounts :many
+SELECT id FROM accounts
+WHERE id = ANY(@ids::uuid[])
+ORDER BY id
+FOR UPDATE;
+
+-- name: CreateEntry :one
+INSERT INTO entries (account_id, operation, currency, amount, balance, available_credit, held, idempotency_key, counterpart_id)
+VALUES (@account_id, @operation, @currency, @amount, @balance, @available_credit, @held, @idempotency_key, @counterpart_id)
+RETURNING *;
+
+-- name: GetEntryByIdempotencyKey :one
+SELECT * FROM entries
+WHERE idempotency_key = @idempotency_key::text;
+
+-- name: ListEntries :many
+SELECT * FROM entries
+WHERE account_id = @account_id
+  AND created_at >= @created_from::timestamptz
+  AND created_at < @created_to::timestamptz
+  AND (created_at, id) < (@before_created_at::timestamptz, @before_id::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT @max_entries;
+
+-- name: ListAccountsByCreatedAt :many
+SELECT a.* FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency
+WHERE a.created_at >= @created_from::timestamptz
+  AND a.created_at < @created_to::timestamptz
+  AND b.balance BETWEEN @min_balance::bigint AND @max_balance::bigint
+  AND (@currency::text = '' OR a.currency = @currency::text)
+  AND (@status::text = '' OR a.status = @status::text)
+  AND a.document LIKE @document_prefix::text || '%'
+  AND (a.created_at, a.id) > (@cursor_created_at::timestamptz, @cursor_id::uuid)
+ORDER BY a.created_at, a.id
+LIMIT @max_accounts;
+
+-- name: ListAccountsByCreatedAtDesc :many
+SELECT a.* FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency
+WHERE a.created_at >= @created_from::timestamptz
+  AND a.created_at < @created_to::timestamptz
+  AND b.balance BETWEEN @min_balance::bigint AND @max_balance::bigint
+  AND (@currency::text = '' OR a.currency = @currency::text)
+  AND (@status::text = '' OR a.status = @status::text)
+  AND a.document LIKE @document_prefix::text || '%'
+  AND (a.created_at, a.id) < (@cursor_created_at::timestamptz, @cursor_id::uuid)
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT @max_accounts;
+
+-- balances in different currencies can't be compared, so they are sorted within their currency
+-- name: ListAccountsByBalance :many
+SELECT a.* FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency
+WHERE a.created_at >= @created_from::timestamptz
+  AND a.created_at < @created_to::timestamptz
+  AND b.balance BETWEEN @min_balance::bigint AND @max_balance::bigint
+  AND (@currency::text = '' OR a.currency = @currency::text)
+  AND (@status::text = '' OR a.status = @status::text)
+  AND a.document LIKE @document_prefix::text || '%'
+  AND (b.currency, b.balance, b.account_id) > (@cursor_currency::text, @cursor_balance::bigint, @cursor_id::uuid)
+ORDER BY b.currency, b.balance, b.account_id
+LIMIT @max_accounts;
+
+-- name: ListAccountsByBalanceDesc :many
+SELECT a.* FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency
+WHERE a.created_at >= @created_from::timestamptz
+  AND a.created_at < @created_to::timestamptz
+  AND b.balance BETWEEN @min_balance::bigint AND @max_balance::bigint
+  AND (@currency::text = '' OR a.currency = @currency::text)
+  AND (@status::text = '' OR a.status = @status::text)
+  AND a.document LIKE @document_prefix::text || '%'
+  AND (b.currency, b.balance, b.account_id) < (@cursor_currency::text, @cursor_balance::bigint, @cursor_id::uuid)
+ORDER BY b.currency DESC, b.balance DESC, b.account_id DESC
+LIMIT @max_accounts;
+
+-- name: CreateOutboxEvent :exec
+INSERT INTO outbox (account_id, event_type, payload)
+VALUES (@account_id, @event_type, @payload);
+
+-- name: LockOutbox :one
+SELECT pg_try_advisory_xact_lock(@lock_key::bigint)::boolean AS locked;
+
+-- name: ListPendingEvents :many
+SELECT * FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT @max_events;
+
+-- name: MarkEventsPublished :exec
+UPDATE outbox SET published_at = CURRENT_TIMESTAMP
+WHERE id = ANY(@ids::bigint[]);
+
+-- name: CreateHold :one
+INSERT INTO holds (account_id, currency, amount, idempotency_key, expires_at)
+VALUES (@account_id, @currency, @amount, @idempotency_key, @expires_at)
+RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = @id;
+
+-- name: GetHoldByIdempotencyKey :one
+SELECT * FROM holds
+WHERE idempotency_key = @idempotency_key::text;
+
+-- name: LockHold :one
+SELECT * FROM holds
+WHERE id = @id
+FOR UPDATE;
+
+-- name: SettleHold :one
+UPDATE holds
+SET status = @status,
+    captured_amount = @captured_amount,
+    entry_id = @entry_id
+WHERE id = @id
+RETURNING *;
+
+-- name: LockExpiredHolds :many
+SELECT * FROM holds
+WHERE status = 'active' AND expires_at <= @now::timestamptz
+ORDER BY expires_at
+LIMIT @max_holds
+FOR UPDATE SKIP LOCKED;
@@ -3,14 +3,79 @@
 package sqlc
 
 import (
+	"database/sql"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
 )
 
 type Account struct {
-	ID              string    `json:"id"`
-	Document        string    `json:"document"`
+	ID           string         `json:"id"`
+	Document     string         `json:"document"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	Status       string         `json:"status"`
+	DocumentType sql.NullString `json:"document_type"`
+	Currency     string         `json:"currency"`
+}
+
+type Balance struct {
+	AccountID       string    `json:"account_id"`
+	Currency        string    `json:"currency"`
 	Balance         int64     `json:"balance"`
+	CreditLimit     int64     `json:"credit_limit"`
 	AvailableCredit int64     `json:"available_credit"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
+	Held            int64     `json:"held"`
+}
+
+type CreditLimitChange struct {
+	ID            string    `json:"id"`
+	AccountID     string    `json:"account_id"`
+	PreviousLimit int64     `json:"previous_limit"`
+	NewLimit      int64     `json:"new_limit"`
+	ChangedBy     string    `json:"changed_by"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+	Currency      string    `json:"currency"`
+}
+
+type Entry struct {
+	ID              string         `json:"id"`
+	AccountID       string         `json:"account_id"`
+	Operation       string         `json:"operation"`
+	Amount          int64          `json:"amount"`
+	Balance         int64          `json:"balance"`
+	AvailableCredit int64          `json:"available_credit"`
+	CreatedAt       time.Time      `json:"created_at"`
+	IdempotencyKey  sql.NullString `json:"idempotency_key"`
+	CounterpartID   uuid.NullUUID  `json:"counterpart_id"`
+	Currency        string         `json:"currency"`
+	Held            int64          `json:"held"`
+}
+
+type Hold struct {
+	ID             string         `json:"id"`
+	AccountID      string         `json:"account_id"`
+	Currency       string         `json:"currency"`
+	Amount         int64          `json:"amount"`
+	CapturedAmount int64          `json:"captured_amount"`
+	Status         string         `json:"status"`
+	IdempotencyKey sql.NullString `json:"idempotency_key"`
+	EntryID        uuid.NullUUID  `json:"entry_id"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+type Outbox struct {
+	ID          int64        `json:"id"`
+	EventID     string       `json:"event_id"`
+	AccountID   string       `json:"account_id"`
+	EventType   string       `json:"event_type"`
+	Payload     pgtype.JSONB `json:"payload"`
+	CreatedAt   time.Time    `json:"created_at"`
+	PublishedAt sql.NullTime `json:"published_at"`
 }
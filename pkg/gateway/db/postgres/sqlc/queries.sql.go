@@ -5,67 +5,309 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgtype"
 )
 
+const captureHeldFunds = `-- name: CaptureHeldFunds :one
+UPDATE balances
+SET balance = balance - $1,
+    held = held - $2
+WHERE balances.account_id = $3 AND balances.currency = $4
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = $3 AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held
+`
+
+type CaptureHeldFundsParams struct {
+	CapturedAmount int64  `json:"captured_amount"`
+	HeldAmount     int64  `json:"held_amount"`
+	AccountID      string `json:"account_id"`
+	Currency       string `json:"currency"`
+}
+
+type CaptureHeldFundsRow struct {
+	Balance         int64 `json:"balance"`
+	AvailableCredit int64 `json:"available_credit"`
+	Held            int64 `json:"held"`
+}
+
+// locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+func (q *Queries) CaptureHeldFunds(ctx context.Context, arg CaptureHeldFundsParams) (CaptureHeldFundsRow, error) {
+	row := q.db.QueryRow(ctx, captureHeldFunds,
+		arg.CapturedAmount,
+		arg.HeldAmount,
+		arg.AccountID,
+		arg.Currency,
+	)
+	var i CaptureHeldFundsRow
+	err := row.Scan(&i.Balance, &i.AvailableCredit, &i.Held)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
-INSERT INTO accounts (document, balance, available_credit)
+INSERT INTO accounts (document, document_type, currency)
 VALUES ($1, $2, $3)
 RETURNING id
 `
 
 type CreateAccountParams struct {
-	Document        string `json:"document"`
-	Balance         int64  `json:"balance"`
-	AvailableCredit int64  `json:"available_credit"`
+	Document     string         `json:"document"`
+	DocumentType sql.NullString `json:"document_type"`
+	Currency     string         `json:"currency"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (string, error) {
-	row := q.db.QueryRow(ctx, createAccount, arg.Document, arg.Balance, arg.AvailableCredit)
+	row := q.db.QueryRow(ctx, createAccount, arg.Document, arg.DocumentType, arg.Currency)
 	var id string
 	err := row.Scan(&id)
 	return id, err
 }
 
-const decreaseAvailableCredit = `-- name: DecreaseAvailableCredit :execrows
-UPDATE accounts
+const createBalance = `-- name: CreateBalance :exec
+INSERT INTO balances (account_id, currency, balance, credit_limit, available_credit)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateBalanceParams struct {
+	AccountID       string `json:"account_id"`
+	Currency        string `json:"currency"`
+	Balance         int64  `json:"balance"`
+	CreditLimit     int64  `json:"credit_limit"`
+	AvailableCredit int64  `json:"available_credit"`
+}
+
+func (q *Queries) CreateBalance(ctx context.Context, arg CreateBalanceParams) error {
+	_, err := q.db.Exec(ctx, createBalance,
+		arg.AccountID,
+		arg.Currency,
+		arg.Balance,
+		arg.CreditLimit,
+		arg.AvailableCredit,
+	)
+	return err
+}
+
+const createCreditLimitChange = `-- name: CreateCreditLimitChange :one
+INSERT INTO credit_limit_changes (account_id, currency, previous_limit, new_limit, changed_by, reason)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, account_id, previous_limit, new_limit, changed_by, reason, created_at, currency
+`
+
+type CreateCreditLimitChangeParams struct {
+	AccountID     string `json:"account_id"`
+	Currency      string `json:"currency"`
+	PreviousLimit int64  `json:"previous_limit"`
+	NewLimit      int64  `json:"new_limit"`
+	ChangedBy     string `json:"changed_by"`
+	Reason        string `json:"reason"`
+}
+
+func (q *Queries) CreateCreditLimitChange(ctx context.Context, arg CreateCreditLimitChangeParams) (CreditLimitChange, error) {
+	row := q.db.QueryRow(ctx, createCreditLimitChange,
+		arg.AccountID,
+		arg.Currency,
+		arg.PreviousLimit,
+		arg.NewLimit,
+		arg.ChangedBy,
+		arg.Reason,
+	)
+	var i CreditLimitChange
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.PreviousLimit,
+		&i.NewLimit,
+		&i.ChangedBy,
+		&i.Reason,
+		&i.CreatedAt,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const createEntry = `-- name: CreateEntry :one
+INSERT INTO entries (account_id, operation, currency, amount, balance, available_credit, held, idempotency_key, counterpart_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, account_id, operation, amount, balance, available_credit, created_at, idempotency_key, counterpart_id, currency, held
+`
+
+type CreateEntryParams struct {
+	AccountID       string         `json:"account_id"`
+	Operation       string         `json:"operation"`
+	Currency        string         `json:"currency"`
+	Amount          int64          `json:"amount"`
+	Balance         int64          `json:"balance"`
+	AvailableCredit int64          `json:"available_credit"`
+	Held            int64          `json:"held"`
+	IdempotencyKey  sql.NullString `json:"idempotency_key"`
+	CounterpartID   uuid.NullUUID  `json:"counterpart_id"`
+}
+
+func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	row := q.db.QueryRow(ctx, createEntry,
+		arg.AccountID,
+		arg.Operation,
+		arg.Currency,
+		arg.Amount,
+		arg.Balance,
+		arg.AvailableCredit,
+		arg.Held,
+		arg.IdempotencyKey,
+		arg.CounterpartID,
+	)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Operation,
+		&i.Amount,
+		&i.Balance,
+		&i.AvailableCredit,
+		&i.CreatedAt,
+		&i.IdempotencyKey,
+		&i.CounterpartID,
+		&i.Currency,
+		&i.Held,
+	)
+	return i, err
+}
+
+const createHold = `-- name: CreateHold :one
+INSERT INTO holds (account_id, currency, amount, idempotency_key, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, account_id, currency, amount, captured_amount, status, idempotency_key, entry_id, expires_at, created_at, updated_at
+`
+
+type CreateHoldParams struct {
+	AccountID      string         `json:"account_id"`
+	Currency       string         `json:"currency"`
+	Amount         int64          `json:"amount"`
+	IdempotencyKey sql.NullString `json:"idempotency_key"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+}
+
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, createHold,
+		arg.AccountID,
+		arg.Currency,
+		arg.Amount,
+		arg.IdempotencyKey,
+		arg.ExpiresAt,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.IdempotencyKey,
+		&i.EntryID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createOutboxEvent = `-- name: CreateOutboxEvent :exec
+INSERT INTO outbox (account_id, event_type, payload)
+VALUES ($1, $2, $3)
+`
+
+type CreateOutboxEventParams struct {
+	AccountID string       `json:"account_id"`
+	EventType string       `json:"event_type"`
+	Payload   pgtype.JSONB `json:"payload"`
+}
+
+func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error {
+	_, err := q.db.Exec(ctx, createOutboxEvent, arg.AccountID, arg.EventType, arg.Payload)
+	return err
+}
+
+const decreaseAvailableCredit = `-- name: DecreaseAvailableCredit :one
+UPDATE balances
 SET available_credit = available_credit - $1
-WHERE id = $2
+WHERE balances.account_id = $2 AND balances.currency = $3 AND (available_credit >= $1)
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = $2 AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held
 `
 
 type DecreaseAvailableCreditParams struct {
-	Amount int64  `json:"amount"`
-	ID     string `json:"id"`
+	Amount    int64  `json:"amount"`
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
 }
 
-func (q *Queries) DecreaseAvailableCredit(ctx context.Context, arg DecreaseAvailableCreditParams) (int64, error) {
-	result, err := q.db.Exec(ctx, decreaseAvailableCredit, arg.Amount, arg.ID)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+type DecreaseAvailableCreditRow struct {
+	Balance         int64 `json:"balance"`
+	AvailableCredit int64 `json:"available_credit"`
+	Held            int64 `json:"held"`
 }
 
-const deposit = `-- name: Deposit :execrows
-UPDATE accounts
+// locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+func (q *Queries) DecreaseAvailableCredit(ctx context.Context, arg DecreaseAvailableCreditParams) (DecreaseAvailableCreditRow, error) {
+	row := q.db.QueryRow(ctx, decreaseAvailableCredit, arg.Amount, arg.AccountID, arg.Currency)
+	var i DecreaseAvailableCreditRow
+	err := row.Scan(&i.Balance, &i.AvailableCredit, &i.Held)
+	return i, err
+}
+
+const deposit = `-- name: Deposit :one
+UPDATE balances
 SET balance = balance + $1
-WHERE id = $2
+WHERE balances.account_id = $2 AND balances.currency = $3
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = $2 AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held
 `
 
 type DepositParams struct {
-	Amount int64  `json:"amount"`
-	ID     string `json:"id"`
+	Amount    int64  `json:"amount"`
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
 }
 
-func (q *Queries) Deposit(ctx context.Context, arg DepositParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deposit, arg.Amount, arg.ID)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+type DepositRow struct {
+	Balance         int64 `json:"balance"`
+	AvailableCredit int64 `json:"available_credit"`
+	Held            int64 `json:"held"`
+}
+
+// locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+func (q *Queries) Deposit(ctx context.Context, arg DepositParams) (DepositRow, error) {
+	row := q.db.QueryRow(ctx, deposit, arg.Amount, arg.AccountID, arg.Currency)
+	var i DepositRow
+	err := row.Scan(&i.Balance, &i.AvailableCredit, &i.Held)
+	return i, err
+}
+
+const getAccountByDocument = `-- name: GetAccountByDocument :one
+SELECT id, document, created_at, updated_at, status, document_type, currency FROM accounts
+WHERE document = $1
+`
+
+func (q *Queries) GetAccountByDocument(ctx context.Context, document string) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountByDocument, document)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Document,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.DocumentType,
+		&i.Currency,
+	)
+	return i, err
 }
 
 const getAccountByID = `-- name: GetAccountByID :one
-SELECT id, document, balance, available_credit, created_at, updated_at FROM accounts
+SELECT id, document, created_at, updated_at, status, document_type, currency FROM accounts
 WHERE id = $1
 `
 
@@ -75,29 +317,876 @@ func (q *Queries) GetAccountByID(ctx context.Context, id string) (Account, error
 	err := row.Scan(
 		&i.ID,
 		&i.Document,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.DocumentType,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const getBalance = `-- name: GetBalance :one
+SELECT account_id, currency, balance, credit_limit, available_credit, created_at, updated_at, held FROM balances
+WHERE account_id = $1 AND currency = $2
+`
+
+type GetBalanceParams struct {
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+}
+
+func (q *Queries) GetBalance(ctx context.Context, arg GetBalanceParams) (Balance, error) {
+	row := q.db.QueryRow(ctx, getBalance, arg.AccountID, arg.Currency)
+	var i Balance
+	err := row.Scan(
+		&i.AccountID,
+		&i.Currency,
+		&i.Balance,
+		&i.CreditLimit,
+		&i.AvailableCredit,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Held,
+	)
+	return i, err
+}
+
+const getEntryByIdempotencyKey = `-- name: GetEntryByIdempotencyKey :one
+SELECT id, account_id, operation, amount, balance, available_credit, created_at, idempotency_key, counterpart_id, currency, held FROM entries
+WHERE idempotency_key = $1::text
+`
+
+func (q *Queries) GetEntryByIdempotencyKey(ctx context.Context, idempotencyKey string) (Entry, error) {
+	row := q.db.QueryRow(ctx, getEntryByIdempotencyKey, idempotencyKey)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Operation,
+		&i.Amount,
+		&i.Balance,
+		&i.AvailableCredit,
+		&i.CreatedAt,
+		&i.IdempotencyKey,
+		&i.CounterpartID,
+		&i.Currency,
+		&i.Held,
+	)
+	return i, err
+}
+
+const getHold = `-- name: GetHold :one
+SELECT id, account_id, currency, amount, captured_amount, status, idempotency_key, entry_id, expires_at, created_at, updated_at FROM holds
+WHERE id = $1
+`
+
+func (q *Queries) GetHold(ctx context.Context, id string) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.IdempotencyKey,
+		&i.EntryID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHoldByIdempotencyKey = `-- name: GetHoldByIdempotencyKey :one
+SELECT id, account_id, currency, amount, captured_amount, status, idempotency_key, entry_id, expires_at, created_at, updated_at FROM holds
+WHERE idempotency_key = $1::text
+`
+
+func (q *Queries) GetHoldByIdempotencyKey(ctx context.Context, idempotencyKey string) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHoldByIdempotencyKey, idempotencyKey)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.IdempotencyKey,
+		&i.EntryID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const holdFunds = `-- name: HoldFunds :one
+UPDATE balances
+SET held = held + $1
+WHERE balances.account_id = $2 AND balances.currency = $3 AND (balance - held >= $1)
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = $2 AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held
+`
+
+type HoldFundsParams struct {
+	Amount    int64  `json:"amount"`
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+}
+
+type HoldFundsRow struct {
+	Balance         int64 `json:"balance"`
+	AvailableCredit int64 `json:"available_credit"`
+	Held            int64 `json:"held"`
+}
+
+// locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+func (q *Queries) HoldFunds(ctx context.Context, arg HoldFundsParams) (HoldFundsRow, error) {
+	row := q.db.QueryRow(ctx, holdFunds, arg.Amount, arg.AccountID, arg.Currency)
+	var i HoldFundsRow
+	err := row.Scan(&i.Balance, &i.AvailableCredit, &i.Held)
+	return i, err
+}
+
+const increaseAvailableCredit = `-- name: IncreaseAvailableCredit :one
+UPDATE balances
+SET available_credit = available_credit + $1
+WHERE balances.account_id = $2 AND balances.currency = $3 AND (available_credit + $1 <= credit_limit)
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = $2 AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held
+`
+
+type IncreaseAvailableCreditParams struct {
+	Amount    int64  `json:"amount"`
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+}
+
+type IncreaseAvailableCreditRow struct {
+	Balance         int64 `json:"balance"`
+	AvailableCredit int64 `json:"available_credit"`
+	Held            int64 `json:"held"`
+}
+
+// locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+func (q *Queries) IncreaseAvailableCredit(ctx context.Context, arg IncreaseAvailableCreditParams) (IncreaseAvailableCreditRow, error) {
+	row := q.db.QueryRow(ctx, increaseAvailableCredit, arg.Amount, arg.AccountID, arg.Currency)
+	var i IncreaseAvailableCreditRow
+	err := row.Scan(&i.Balance, &i.AvailableCredit, &i.Held)
+	return i, err
+}
+
+const listAccountsByBalance = `-- name: ListAccountsByBalance :many
+SELECT a.id, a.document, a.created_at, a.updated_at, a.status, a.document_type, a.currency FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency
+WHERE a.created_at >= $1::timestamptz
+  AND a.created_at < $2::timestamptz
+  AND b.balance BETWEEN $3::bigint AND $4::bigint
+  AND ($5::text = '' OR a.currency = $5::text)
+  AND ($6::text = '' OR a.status = $6::text)
+  AND a.document LIKE $7::text || '%'
+  AND (b.currency, b.balance, b.account_id) > ($8::text, $9::bigint, $10::uuid)
+ORDER BY b.currency, b.balance, b.account_id
+LIMIT $11
+`
+
+type ListAccountsByBalanceParams struct {
+	CreatedFrom    time.Time `json:"created_from"`
+	CreatedTo      time.Time `json:"created_to"`
+	MinBalance     int64     `json:"min_balance"`
+	MaxBalance     int64     `json:"max_balance"`
+	Currency       string    `json:"currency"`
+	Status         string    `json:"status"`
+	DocumentPrefix string    `json:"document_prefix"`
+	CursorCurrency string    `json:"cursor_currency"`
+	CursorBalance  int64     `json:"cursor_balance"`
+	CursorID       string    `json:"cursor_id"`
+	MaxAccounts    int32     `json:"max_accounts"`
+}
+
+// balances in different currencies can't be compared, so they are sorted within their currency
+func (q *Queries) ListAccountsByBalance(ctx context.Context, arg ListAccountsByBalanceParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsByBalance,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinBalance,
+		arg.MaxBalance,
+		arg.Currency,
+		arg.Status,
+		arg.DocumentPrefix,
+		arg.CursorCurrency,
+		arg.CursorBalance,
+		arg.CursorID,
+		arg.MaxAccounts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Document,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.DocumentType,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsByBalanceDesc = `-- name: ListAccountsByBalanceDesc :many
+SELECT a.id, a.document, a.created_at, a.updated_at, a.status, a.document_type, a.currency FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency
+WHERE a.created_at >= $1::timestamptz
+  AND a.created_at < $2::timestamptz
+  AND b.balance BETWEEN $3::bigint AND $4::bigint
+  AND ($5::text = '' OR a.currency = $5::text)
+  AND ($6::text = '' OR a.status = $6::text)
+  AND a.document LIKE $7::text || '%'
+  AND (b.currency, b.balance, b.account_id) < ($8::text, $9::bigint, $10::uuid)
+ORDER BY b.currency DESC, b.balance DESC, b.account_id DESC
+LIMIT $11
+`
+
+type ListAccountsByBalanceDescParams struct {
+	CreatedFrom    time.Time `json:"created_from"`
+	CreatedTo      time.Time `json:"created_to"`
+	MinBalance     int64     `json:"min_balance"`
+	MaxBalance     int64     `json:"max_balance"`
+	Currency       string    `json:"currency"`
+	Status         string    `json:"status"`
+	DocumentPrefix string    `json:"document_prefix"`
+	CursorCurrency string    `json:"cursor_currency"`
+	CursorBalance  int64     `json:"cursor_balance"`
+	CursorID       string    `json:"cursor_id"`
+	MaxAccounts    int32     `json:"max_accounts"`
+}
+
+func (q *Queries) ListAccountsByBalanceDesc(ctx context.Context, arg ListAccountsByBalanceDescParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsByBalanceDesc,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinBalance,
+		arg.MaxBalance,
+		arg.Currency,
+		arg.Status,
+		arg.DocumentPrefix,
+		arg.CursorCurrency,
+		arg.CursorBalance,
+		arg.CursorID,
+		arg.MaxAccounts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Document,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.DocumentType,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsByCreatedAt = `-- name: ListAccountsByCreatedAt :many
+SELECT a.id, a.document, a.created_at, a.updated_at, a.status, a.document_type, a.currency FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency
+WHERE a.created_at >= $1::timestamptz
+  AND a.created_at < $2::timestamptz
+  AND b.balance BETWEEN $3::bigint AND $4::bigint
+  AND ($5::text = '' OR a.currency = $5::text)
+  AND ($6::text = '' OR a.status = $6::text)
+  AND a.document LIKE $7::text || '%'
+  AND (a.created_at, a.id) > ($8::timestamptz, $9::uuid)
+ORDER BY a.created_at, a.id
+LIMIT $10
+`
+
+type ListAccountsByCreatedAtParams struct {
+	CreatedFrom     time.Time `json:"created_from"`
+	CreatedTo       time.Time `json:"created_to"`
+	MinBalance      int64     `json:"min_balance"`
+	MaxBalance      int64     `json:"max_balance"`
+	Currency        string    `json:"currency"`
+	Status          string    `json:"status"`
+	DocumentPrefix  string    `json:"document_prefix"`
+	CursorCreatedAt time.Time `json:"cursor_created_at"`
+	CursorID        string    `json:"cursor_id"`
+	MaxAccounts     int32     `json:"max_accounts"`
+}
+
+func (q *Queries) ListAccountsByCreatedAt(ctx context.Context, arg ListAccountsByCreatedAtParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsByCreatedAt,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinBalance,
+		arg.MaxBalance,
+		arg.Currency,
+		arg.Status,
+		arg.DocumentPrefix,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.MaxAccounts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Document,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.DocumentType,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsByCreatedAtDesc = `-- name: ListAccountsByCreatedAtDesc :many
+SELECT a.id, a.document, a.created_at, a.updated_at, a.status, a.document_type, a.currency FROM accounts a
+JOIN balances b ON b.account_id = a.id AND b.currency = a.currency
+WHERE a.created_at >= $1::timestamptz
+  AND a.created_at < $2::timestamptz
+  AND b.balance BETWEEN $3::bigint AND $4::bigint
+  AND ($5::text = '' OR a.currency = $5::text)
+  AND ($6::text = '' OR a.status = $6::text)
+  AND a.document LIKE $7::text || '%'
+  AND (a.created_at, a.id) < ($8::timestamptz, $9::uuid)
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT $10
+`
+
+type ListAccountsByCreatedAtDescParams struct {
+	CreatedFrom     time.Time `json:"created_from"`
+	CreatedTo       time.Time `json:"created_to"`
+	MinBalance      int64     `json:"min_balance"`
+	MaxBalance      int64     `json:"max_balance"`
+	Currency        string    `json:"currency"`
+	Status          string    `json:"status"`
+	DocumentPrefix  string    `json:"document_prefix"`
+	CursorCreatedAt time.Time `json:"cursor_created_at"`
+	CursorID        string    `json:"cursor_id"`
+	MaxAccounts     int32     `json:"max_accounts"`
+}
+
+func (q *Queries) ListAccountsByCreatedAtDesc(ctx context.Context, arg ListAccountsByCreatedAtDescParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsByCreatedAtDesc,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinBalance,
+		arg.MaxBalance,
+		arg.Currency,
+		arg.Status,
+		arg.DocumentPrefix,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.MaxAccounts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Document,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.DocumentType,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBalances = `-- name: ListBalances :many
+SELECT account_id, currency, balance, credit_limit, available_credit, created_at, updated_at, held FROM balances
+WHERE account_id = ANY($1::uuid[])
+ORDER BY account_id, currency
+`
+
+func (q *Queries) ListBalances(ctx context.Context, accountIds []string) ([]Balance, error) {
+	rows, err := q.db.Query(ctx, listBalances, accountIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Balance
+	for rows.Next() {
+		var i Balance
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Currency,
+			&i.Balance,
+			&i.CreditLimit,
+			&i.AvailableCredit,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Held,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntries = `-- name: ListEntries :many
+SELECT id, account_id, operation, amount, balance, available_credit, created_at, idempotency_key, counterpart_id, currency, held FROM entries
+WHERE account_id = $1
+  AND created_at >= $2::timestamptz
+  AND created_at < $3::timestamptz
+  AND (created_at, id) < ($4::timestamptz, $5::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $6
+`
+
+type ListEntriesParams struct {
+	AccountID       string    `json:"account_id"`
+	CreatedFrom     time.Time `json:"created_from"`
+	CreatedTo       time.Time `json:"created_to"`
+	BeforeCreatedAt time.Time `json:"before_created_at"`
+	BeforeID        string    `json:"before_id"`
+	MaxEntries      int32     `json:"max_entries"`
+}
+
+func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listEntries,
+		arg.AccountID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.MaxEntries,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Entry
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Operation,
+			&i.Amount,
+			&i.Balance,
+			&i.AvailableCredit,
+			&i.CreatedAt,
+			&i.IdempotencyKey,
+			&i.CounterpartID,
+			&i.Currency,
+			&i.Held,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingEvents = `-- name: ListPendingEvents :many
+SELECT id, event_id, account_id, event_type, payload, created_at, published_at FROM outbox
+WHERE published_at IS NULL
+ORDER BY id
+LIMIT $1
+`
+
+func (q *Queries) ListPendingEvents(ctx context.Context, maxEvents int32) ([]Outbox, error) {
+	rows, err := q.db.Query(ctx, listPendingEvents, maxEvents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Outbox
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.AccountID,
+			&i.EventType,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockAccount = `-- name: LockAccount :one
+SELECT id, document, created_at, updated_at, status, document_type, currency FROM accounts
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockAccount(ctx context.Context, id string) (Account, error) {
+	row := q.db.QueryRow(ctx, lockAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Document,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.DocumentType,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const lockAccounts = `-- name: LockAccounts :many
+SELECT id FROM accounts
+WHERE id = ANY($1::uuid[])
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockAccounts(ctx context.Context, ids []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, lockAccounts, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockBalance = `-- name: LockBalance :one
+SELECT account_id, currency, balance, credit_limit, available_credit, created_at, updated_at, held FROM balances
+WHERE account_id = $1 AND currency = $2
+FOR UPDATE
+`
+
+type LockBalanceParams struct {
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+}
+
+func (q *Queries) LockBalance(ctx context.Context, arg LockBalanceParams) (Balance, error) {
+	row := q.db.QueryRow(ctx, lockBalance, arg.AccountID, arg.Currency)
+	var i Balance
+	err := row.Scan(
+		&i.AccountID,
+		&i.Currency,
 		&i.Balance,
+		&i.CreditLimit,
 		&i.AvailableCredit,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Held,
+	)
+	return i, err
+}
+
+const lockExpiredHolds = `-- name: LockExpiredHolds :many
+SELECT id, account_id, currency, amount, captured_amount, status, idempotency_key, entry_id, expires_at, created_at, updated_at FROM holds
+WHERE status = 'active' AND expires_at <= $1::timestamptz
+ORDER BY expires_at
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+type LockExpiredHoldsParams struct {
+	Now      time.Time `json:"now"`
+	MaxHolds int32     `json:"max_holds"`
+}
+
+func (q *Queries) LockExpiredHolds(ctx context.Context, arg LockExpiredHoldsParams) ([]Hold, error) {
+	rows, err := q.db.Query(ctx, lockExpiredHolds, arg.Now, arg.MaxHolds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Hold
+	for rows.Next() {
+		var i Hold
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Currency,
+			&i.Amount,
+			&i.CapturedAmount,
+			&i.Status,
+			&i.IdempotencyKey,
+			&i.EntryID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockHold = `-- name: LockHold :one
+SELECT id, account_id, currency, amount, captured_amount, status, idempotency_key, entry_id, expires_at, created_at, updated_at FROM holds
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockHold(ctx context.Context, id string) (Hold, error) {
+	row := q.db.QueryRow(ctx, lockHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.IdempotencyKey,
+		&i.EntryID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const lockOutbox = `-- name: LockOutbox :one
+SELECT pg_try_advisory_xact_lock($1::bigint)::boolean AS locked
+`
+
+func (q *Queries) LockOutbox(ctx context.Context, lockKey int64) (bool, error) {
+	row := q.db.QueryRow(ctx, lockOutbox, lockKey)
+	var locked bool
+	err := row.Scan(&locked)
+	return locked, err
+}
+
+const markEventsPublished = `-- name: MarkEventsPublished :exec
+UPDATE outbox SET published_at = CURRENT_TIMESTAMP
+WHERE id = ANY($1::bigint[])
+`
+
+func (q *Queries) MarkEventsPublished(ctx context.Context, ids []int64) error {
+	_, err := q.db.Exec(ctx, markEventsPublished, ids)
+	return err
+}
+
+const releaseHeldFunds = `-- name: ReleaseHeldFunds :one
+UPDATE balances
+SET held = held - $1
+WHERE account_id = $2 AND currency = $3
+RETURNING balance, available_credit, held
+`
+
+type ReleaseHeldFundsParams struct {
+	Amount    int64  `json:"amount"`
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
+}
+
+type ReleaseHeldFundsRow struct {
+	Balance         int64 `json:"balance"`
+	AvailableCredit int64 `json:"available_credit"`
+	Held            int64 `json:"held"`
+}
+
+// voids and expiries only give held funds back, which accounts get whatever their status,
+// releaseHold locking the account FOR UPDATE beforehand as every other movement does
+func (q *Queries) ReleaseHeldFunds(ctx context.Context, arg ReleaseHeldFundsParams) (ReleaseHeldFundsRow, error) {
+	row := q.db.QueryRow(ctx, releaseHeldFunds, arg.Amount, arg.AccountID, arg.Currency)
+	var i ReleaseHeldFundsRow
+	err := row.Scan(&i.Balance, &i.AvailableCredit, &i.Held)
+	return i, err
+}
+
+const settleHold = `-- name: SettleHold :one
+UPDATE holds
+SET status = $1,
+    captured_amount = $2,
+    entry_id = $3
+WHERE id = $4
+RETURNING id, account_id, currency, amount, captured_amount, status, idempotency_key, entry_id, expires_at, created_at, updated_at
+`
+
+type SettleHoldParams struct {
+	Status         string        `json:"status"`
+	CapturedAmount int64         `json:"captured_amount"`
+	EntryID        uuid.NullUUID `json:"entry_id"`
+	ID             string        `json:"id"`
+}
+
+func (q *Queries) SettleHold(ctx context.Context, arg SettleHoldParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, settleHold,
+		arg.Status,
+		arg.CapturedAmount,
+		arg.EntryID,
+		arg.ID,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.IdempotencyKey,
+		&i.EntryID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const withdraw = `-- name: Withdraw :execrows
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
 UPDATE accounts
+SET status = $1
+WHERE id = $2 AND status = ANY($3::text[])
+RETURNING id, document, created_at, updated_at, status, document_type, currency
+`
+
+type UpdateAccountStatusParams struct {
+	Status       string   `json:"status"`
+	ID           string   `json:"id"`
+	FromStatuses []string `json:"from_statuses"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatus, arg.Status, arg.ID, arg.FromStatuses)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Document,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.DocumentType,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const updateCreditLimit = `-- name: UpdateCreditLimit :exec
+UPDATE balances
+SET credit_limit = $1,
+    available_credit = $2
+WHERE account_id = $3 AND currency = $4
+`
+
+type UpdateCreditLimitParams struct {
+	CreditLimit     int64  `json:"credit_limit"`
+	AvailableCredit int64  `json:"available_credit"`
+	AccountID       string `json:"account_id"`
+	Currency        string `json:"currency"`
+}
+
+func (q *Queries) UpdateCreditLimit(ctx context.Context, arg UpdateCreditLimitParams) error {
+	_, err := q.db.Exec(ctx, updateCreditLimit,
+		arg.CreditLimit,
+		arg.AvailableCredit,
+		arg.AccountID,
+		arg.Currency,
+	)
+	return err
+}
+
+const withdraw = `-- name: Withdraw :one
+UPDATE balances
 SET balance = balance - $1
-WHERE id = $2 AND (balance >= $1)
+WHERE balances.account_id = $2 AND balances.currency = $3 AND (balance - held >= $1)
+  AND EXISTS (SELECT 1 FROM accounts WHERE accounts.id = $2 AND accounts.status = 'active' FOR UPDATE)
+RETURNING balance, available_credit, held
 `
 
 type WithdrawParams struct {
-	Amount int64  `json:"amount"`
-	ID     string `json:"id"`
+	Amount    int64  `json:"amount"`
+	AccountID string `json:"account_id"`
+	Currency  string `json:"currency"`
 }
 
-func (q *Queries) Withdraw(ctx context.Context, arg WithdrawParams) (int64, error) {
-	result, err := q.db.Exec(ctx, withdraw, arg.Amount, arg.ID)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+type WithdrawRow struct {
+	Balance         int64 `json:"balance"`
+	AvailableCredit int64 `json:"available_credit"`
+	Held            int64 `json:"held"`
+}
+
+// locks the account FOR UPDATE so that its outbox events get ids in commit order, whatever the currency
+func (q *Queries) Withdraw(ctx context.Context, arg WithdrawParams) (WithdrawRow, error) {
+	row := q.db.QueryRow(ctx, withdraw, arg.Amount, arg.AccountID, arg.Currency)
+	var i WithdrawRow
+	err := row.Scan(&i.Balance, &i.AvailableCredit, &i.Held)
+	return i, err
 }
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/db/postgres/sqlc"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/tracing"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ sqlc.DBTX = tracedDB{}
+
+// tracedDB opens a span for every query run through sqlc, named after the query itself
+type tracedDB struct {
+	db sqlc.DBTX
+}
+
+func (t tracedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, span := startQuery(ctx, sql)
+	tag, err := t.db.Exec(ctx, sql, args...)
+	tracing.End(span, err)
+	return tag, err
+}
+
+func (t tracedDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, span := startQuery(ctx, sql)
+	rows, err := t.db.Query(ctx, sql, args...)
+	if err != nil {
+		tracing.End(span, err)
+		return rows, err
+	}
+	return tracedRows{Rows: rows, span: span}, nil
+}
+
+func (t tracedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, span := startQuery(ctx, sql)
+	return tracedRow{row: t.db.QueryRow(ctx, sql, args...), span: span}
+}
+
+// tracedRow ends the span once the row is read since that is when results are actually awaited
+type tracedRow struct {
+	row  pgx.Row
+	span trace.Span
+}
+
+func (r tracedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	if err == pgx.ErrNoRows {
+		// not finding rows is an expected outcome rather than a failure
+		tracing.End(r.span, nil)
+		return err
+	}
+	tracing.End(r.span, err)
+	return err
+}
+
+// tracedRows ends the span once rows are closed
+type tracedRows struct {
+	pgx.Rows
+	span trace.Span
+}
+
+func (r tracedRows) Close() {
+	r.Rows.Close()
+	tracing.End(r.span, r.Rows.Err())
+}
+
+func startQuery(ctx context.Context, sql string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, queryName(sql),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBStatementKey.String(sql),
+		),
+	)
+}
+
+// queryName extracts the name out of sqlc generated queries ("-- name: GetAccountByID :one")
+func queryName(sql string) string {
+	const prefix = "-- name: "
+	if !strings.HasPrefix(sql, prefix) {
+		return "postgres.query"
+	}
+
+	fields := strings.Fields(sql[len(prefix):])
+	if len(fields) == 0 {
+		return "postgres.query"
+	}
+	return fields[0]
+}
@@ -2,41 +2,74 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/db/postgres/sqlc"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/tracing"
+	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	pgx_errors "github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 var _ accounts.Repository = &AccountsRepository{}
 
 // AccountsRepository is the repository of accounts
 type AccountsRepository struct {
-	conn *pgx.Conn
+	conn *pgxpool.Pool
 	q    *sqlc.Queries
 }
 
 // NewAccountsRepository returns an acc repository
-func NewAccountsRepository(conn *pgx.Conn) *AccountsRepository {
+func NewAccountsRepository(conn *pgxpool.Pool) *AccountsRepository {
 	return &AccountsRepository{
 		conn: conn,
-		q:    sqlc.New(conn),
+		q:    sqlc.New(tracedDB{conn}),
 	}
 }
 
-// CreateAccount inserts an account on DB returning its ID
-func (r AccountsRepository) CreateAccount(ctx context.Context, acc entities.Account) (vos.AccountID, error) {
+// CreateAccount inserts an account on DB along with its balances and creation event, returning its ID
+func (r AccountsRepository) CreateAccount(ctx context.Context, acc entities.Account) (_ vos.AccountID, err error) {
 	const operation = "postgres.AccountsRepository.CreateAccount"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
 
-	accID, err := r.q.CreateAccount(ctx, sqlc.CreateAccountParams{
-		Document:        acc.Document.String(),
-		Balance:         acc.Balance.Int64(),
-		AvailableCredit: acc.AvailableCredit.Int64(),
+	err = r.inTx(ctx, func(q *sqlc.Queries) error {
+		accID, err := q.CreateAccount(ctx, sqlc.CreateAccountParams{
+			Document: acc.Document.String(),
+			DocumentType: sql.NullString{
+				String: acc.DocumentType.String(),
+				Valid:  acc.DocumentType != "",
+			},
+			Currency: acc.Currency.String(),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, balance := range acc.Balances {
+			err = q.CreateBalance(ctx, sqlc.CreateBalanceParams{
+				AccountID:       accID,
+				Currency:        balance.Currency().String(),
+				Balance:         balance.Balance.Int64(),
+				CreditLimit:     balance.CreditLimit.Int64(),
+				AvailableCredit: balance.AvailableCredit.Int64(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		acc.ID = vos.AccountID(accID)
+		return appendEvent(ctx, q, entities.NewAccountCreatedEvent(acc))
 	})
 	if err != nil {
 		if pgerr, ok := err.(*pgconn.PgError); ok {
@@ -47,12 +80,14 @@ func (r AccountsRepository) CreateAccount(ctx context.Context, acc entities.Acco
 		return "", domain.Error(operation, err)
 	}
 
-	return vos.AccountID(accID), nil
+	return acc.ID, nil
 }
 
 // GetAccountByID retrieves an account by ID
-func (r AccountsRepository) GetAccountByID(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+func (r AccountsRepository) GetAccountByID(ctx context.Context, accID vos.AccountID) (_ entities.Account, err error) {
 	const operation = "postgres.AccountsRepository.GetAccountByID"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
 
 	rawAcc, err := r.q.GetAccountByID(ctx, accID.String())
 	if err != nil {
@@ -62,70 +97,643 @@ func (r AccountsRepository) GetAccountByID(ctx context.Context, accID vos.Accoun
 		return entities.Account{}, domain.Error(operation, err)
 	}
 
-	return mapRawAccount(rawAcc), nil
+	acc, err := withBalances(ctx, r.q, rawAcc)
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	return acc, nil
 }
 
-// Deposit increments account balance
-func (r AccountsRepository) Deposit(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+// GetAccountByDocument retrieves an account by its normalized document
+func (r AccountsRepository) GetAccountByDocument(ctx context.Context, doc vos.Document) (_ entities.Account, err error) {
+	const operation = "postgres.AccountsRepository.GetAccountByDocument"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	rawAcc, err := r.q.GetAccountByDocument(ctx, doc.String())
+	if err != nil {
+		if err == pgx_errors.ErrNoRows {
+			return entities.Account{}, accounts.ErrAccountNotFound
+		}
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	acc, err := withBalances(ctx, r.q, rawAcc)
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	return acc, nil
+}
+
+// ListAccounts lists accounts matching the filter in the requested order, balances being sorted by the main one within its currency
+func (r AccountsRepository) ListAccounts(ctx context.Context, filter entities.AccountFilter) (_ []entities.Account, err error) {
+	const operation = "postgres.AccountsRepository.ListAccounts"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	// the first page starts past whichever end the listing is sorted from
+	cursor, cursorID := filter.After, filter.After.ID.String()
+	if cursor.IsZero() {
+		cursor, cursorID = entities.AccountCursor{Balance: vos.NewMoney(math.MinInt64, "")}, uuid.Nil.String()
+		if strings.HasPrefix(filter.Sort.String(), "-") {
+			cursor, cursorID = entities.AccountCursor{CreatedAt: endOfTimes, Balance: vos.NewMoney(math.MaxInt64, maxCurrency)}, maxUUID
+		}
+	}
+
+	byCreatedAt := sqlc.ListAccountsByCreatedAtParams{
+		CreatedFrom:     filter.CreatedFrom,
+		CreatedTo:       filter.CreatedTo,
+		MinBalance:      math.MinInt64,
+		MaxBalance:      math.MaxInt64,
+		Currency:        filter.Currency.String(),
+		Status:          filter.Status.String(),
+		DocumentPrefix:  filter.DocumentPrefix,
+		CursorCreatedAt: cursor.CreatedAt,
+		CursorID:        cursorID,
+		MaxAccounts:     int32(filter.Limit),
+	}
+	if byCreatedAt.CreatedTo.IsZero() {
+		byCreatedAt.CreatedTo = endOfTimes
+	}
+	if filter.MinBalance != nil {
+		byCreatedAt.MinBalance = filter.MinBalance.Int64()
+	}
+	if filter.MaxBalance != nil {
+		byCreatedAt.MaxBalance = filter.MaxBalance.Int64()
+	}
+
+	byBalance := sqlc.ListAccountsByBalanceParams{
+		CreatedFrom:    byCreatedAt.CreatedFrom,
+		CreatedTo:      byCreatedAt.CreatedTo,
+		MinBalance:     byCreatedAt.MinBalance,
+		MaxBalance:     byCreatedAt.MaxBalance,
+		Currency:       byCreatedAt.Currency,
+		Status:         byCreatedAt.Status,
+		DocumentPrefix: byCreatedAt.DocumentPrefix,
+		CursorCurrency: cursor.Balance.Currency().String(),
+		CursorBalance:  cursor.Balance.Int64(),
+		CursorID:       cursorID,
+		MaxAccounts:    byCreatedAt.MaxAccounts,
+	}
+
+	var rawAccs []sqlc.Account
+	switch filter.Sort {
+	case entities.SortByCreatedAt:
+		rawAccs, err = r.q.ListAccountsByCreatedAt(ctx, byCreatedAt)
+	case entities.SortByCreatedAtDesc:
+		rawAccs, err = r.q.ListAccountsByCreatedAtDesc(ctx, sqlc.ListAccountsByCreatedAtDescParams(byCreatedAt))
+	case entities.SortByBalance:
+		rawAccs, err = r.q.ListAccountsByBalance(ctx, byBalance)
+	case entities.SortByBalanceDesc:
+		rawAccs, err = r.q.ListAccountsByBalanceDesc(ctx, sqlc.ListAccountsByBalanceDescParams(byBalance))
+	default:
+		return nil, accounts.ErrInvalidAccountFilter
+	}
+	if err != nil {
+		return nil, domain.Error(operation, err)
+	}
+
+	accs, err := withAllBalances(ctx, r.q, rawAccs)
+	if err != nil {
+		return nil, domain.Error(operation, err)
+	}
+
+	return accs, nil
+}
+
+// Deposit increments account balance in the amount currency
+func (r AccountsRepository) Deposit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
 	const operation = "postgres.AccountsRepository.Deposit"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
 
-	rows, err := r.q.Deposit(ctx, sqlc.DepositParams{
-		ID:     accID.String(),
-		Amount: amount.Int64(),
+	entry, err := r.registerEntry(ctx, key, accID, entities.OperationDeposit, amount, func(q *sqlc.Queries) (balances, error) {
+		row, err := q.Deposit(ctx, sqlc.DepositParams{
+			AccountID: accID.String(),
+			Currency:  amount.Currency().String(),
+			Amount:    amount.Int64(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return balances{}, diagnoseUpdate(ctx, q, accID, amount.Currency(), accounts.ErrAccountNotFound)
+		}
+		return balances(row), err
 	})
 	if err != nil {
-		return domain.Error(operation, err)
-	}
-	if rows == 0 {
-		return accounts.ErrAccountNotFound
+		return entities.Entry{}, domain.Error(operation, err)
 	}
 
-	return nil
+	return entry, nil
 }
 
-// Withdraw decreases account balance
-func (r AccountsRepository) Withdraw(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+// Withdraw decreases account balance in the amount currency
+func (r AccountsRepository) Withdraw(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
 	const operation = "postgres.AccountsRepository.Withdraw"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
 
-	rows, err := r.q.Withdraw(ctx, sqlc.WithdrawParams{
-		ID:     accID.String(),
-		Amount: amount.Int64(),
+	entry, err := r.registerEntry(ctx, key, accID, entities.OperationWithdrawal, amount, func(q *sqlc.Queries) (balances, error) {
+		row, err := q.Withdraw(ctx, sqlc.WithdrawParams{
+			AccountID: accID.String(),
+			Currency:  amount.Currency().String(),
+			Amount:    amount.Int64(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return balances{}, diagnoseUpdate(ctx, q, accID, amount.Currency(), accounts.ErrInsufficientBalance)
+		}
+		return balances(row), err
 	})
 	if err != nil {
-		return domain.Error(operation, err)
-	}
-	if rows == 0 {
-		return accounts.ErrInsufficientBalance
+		return entities.Entry{}, domain.Error(operation, err)
 	}
 
-	return nil
+	return entry, nil
 }
 
-// DecreaseAvailableCredit decreases account available credit
-func (r AccountsRepository) DecreaseAvailableCredit(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+// DecreaseAvailableCredit decreases account available credit in the amount currency
+func (r AccountsRepository) DecreaseAvailableCredit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
 	const operation = "postgres.AccountsRepository.DecreaseAvailableCredit"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	entry, err := r.registerEntry(ctx, key, accID, entities.OperationCreditReservation, amount, func(q *sqlc.Queries) (balances, error) {
+		row, err := q.DecreaseAvailableCredit(ctx, sqlc.DecreaseAvailableCreditParams{
+			AccountID: accID.String(),
+			Currency:  amount.Currency().String(),
+			Amount:    amount.Int64(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return balances{}, diagnoseUpdate(ctx, q, accID, amount.Currency(), accounts.ErrInsufficientCredit)
+		}
+		return balances(row), err
+	})
+	if err != nil {
+		return entities.Entry{}, domain.Error(operation, err)
+	}
+
+	return entry, nil
+}
+
+// IncreaseAvailableCredit increases account available credit in the amount currency up to its credit limit
+func (r AccountsRepository) IncreaseAvailableCredit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (_ entities.Entry, err error) {
+	const operation = "postgres.AccountsRepository.IncreaseAvailableCredit"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	entry, err := r.registerEntry(ctx, key, accID, entities.OperationCreditRelease, amount, func(q *sqlc.Queries) (balances, error) {
+		row, err := q.IncreaseAvailableCredit(ctx, sqlc.IncreaseAvailableCreditParams{
+			AccountID: accID.String(),
+			Currency:  amount.Currency().String(),
+			Amount:    amount.Int64(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return balances{}, diagnoseUpdate(ctx, q, accID, amount.Currency(), accounts.ErrCreditLimitExceeded)
+		}
+		return balances(row), err
+	})
+	if err != nil {
+		return entities.Entry{}, domain.Error(operation, err)
+	}
+
+	return entry, nil
+}
+
+// UpdateAccountStatus moves an account to a new status as long as it currently is in one of the allowed ones
+func (r AccountsRepository) UpdateAccountStatus(ctx context.Context, accID vos.AccountID, transition entities.StatusTransition) (_ entities.Account, err error) {
+	const operation = "postgres.AccountsRepository.UpdateAccountStatus"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	from := make([]string, 0, len(transition.From))
+	for _, status := range transition.From {
+		from = append(from, status.String())
+	}
+
+	rawAcc, err := r.q.UpdateAccountStatus(ctx, sqlc.UpdateAccountStatusParams{
+		ID:           accID.String(),
+		Status:       transition.To.String(),
+		FromStatuses: from,
+	})
+	if err == pgx_errors.ErrNoRows {
+		_, err = r.q.GetAccountByID(ctx, accID.String())
+		if err == pgx_errors.ErrNoRows {
+			return entities.Account{}, accounts.ErrAccountNotFound
+		}
+		if err == nil {
+			return entities.Account{}, accounts.ErrInvalidStatusTransition
+		}
+	}
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	acc, err := withBalances(ctx, r.q, rawAcc)
+	if err != nil {
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	return acc, nil
+}
+
+// UpdateCreditLimit sets a new credit limit on the balance in its currency and records who changed it and why.
+// Available credit moves along with the limit so that reserved credit is kept as is.
+func (r AccountsRepository) UpdateCreditLimit(ctx context.Context, change entities.CreditLimitChange) (_ entities.Account, err error) {
+	const operation = "postgres.AccountsRepository.UpdateCreditLimit"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	var acc entities.Account
+	err = r.inTx(ctx, func(q *sqlc.Queries) error {
+		rawAcc, err := q.LockAccount(ctx, change.AccountID.String())
+		if err == pgx_errors.ErrNoRows {
+			return accounts.ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if entities.AccountStatus(rawAcc.Status) == entities.AccountStatusClosed {
+			return accounts.ErrAccountNotActive
+		}
+
+		currency := change.NewLimit.Currency()
+		locked, err := q.LockBalance(ctx, sqlc.LockBalanceParams{
+			AccountID: change.AccountID.String(),
+			Currency:  currency.String(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return accounts.ErrCurrencyMismatch
+		}
+		if err != nil {
+			return err
+		}
+
+		balance := mapRawBalance(locked)
+		reserved, err := balance.ReservedCredit()
+		if err != nil {
+			return accounts.ErrAmountOutOfRange
+		}
+
+		available, err := change.NewLimit.Sub(reserved)
+		if err != nil {
+			return accounts.ErrAmountOutOfRange
+		}
+		if available.Sign() < 0 {
+			return accounts.ErrCreditLimitBelowReserved
+		}
+
+		err = q.UpdateCreditLimit(ctx, sqlc.UpdateCreditLimitParams{
+			AccountID:       change.AccountID.String(),
+			Currency:        currency.String(),
+			CreditLimit:     change.NewLimit.Int64(),
+			AvailableCredit: available.Int64(),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateCreditLimitChange(ctx, sqlc.CreateCreditLimitChangeParams{
+			AccountID:     change.AccountID.String(),
+			Currency:      currency.String(),
+			PreviousLimit: balance.CreditLimit.Int64(),
+			NewLimit:      change.NewLimit.Int64(),
+			ChangedBy:     change.ChangedBy,
+			Reason:        change.Reason,
+		})
+		if err != nil {
+			return err
+		}
 
-	rows, err := r.q.DecreaseAvailableCredit(ctx, sqlc.DecreaseAvailableCreditParams{
-		ID:     accID.String(),
-		Amount: amount.Int64(),
+		acc, err = withBalances(ctx, q, rawAcc)
+		return err
 	})
 	if err != nil {
-		return domain.Error(operation, err)
+		return entities.Account{}, domain.Error(operation, err)
+	}
+
+	return acc, nil
+}
+
+// GetEntryByIdempotencyKey retrieves the ledger entry registered under an idempotency key
+func (r AccountsRepository) GetEntryByIdempotencyKey(ctx context.Context, key vos.IdempotencyKey) (_ entities.Entry, err error) {
+	const operation = "postgres.AccountsRepository.GetEntryByIdempotencyKey"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	rawEntry, err := r.q.GetEntryByIdempotencyKey(ctx, key.String())
+	if err != nil {
+		if err == pgx_errors.ErrNoRows {
+			return entities.Entry{}, accounts.ErrEntryNotFound
+		}
+		return entities.Entry{}, domain.Error(operation, err)
 	}
-	if rows == 0 {
+
+	return mapRawEntry(rawEntry), nil
+}
+
+// diagnoseUpdate tells apart a missing or non active account, or one not holding the currency, from a failed update condition
+func diagnoseUpdate(ctx context.Context, q *sqlc.Queries, accID vos.AccountID, currency vos.Currency, conditionErr error) error {
+	rawAcc, err := q.GetAccountByID(ctx, accID.String())
+	if err == pgx_errors.ErrNoRows {
 		return accounts.ErrAccountNotFound
 	}
+	if err != nil {
+		return err
+	}
+	if entities.AccountStatus(rawAcc.Status) != entities.AccountStatusActive {
+		return accounts.ErrAccountNotActive
+	}
+
+	_, err = q.GetBalance(ctx, sqlc.GetBalanceParams{
+		AccountID: accID.String(),
+		Currency:  currency.String(),
+	})
+	if err == pgx_errors.ErrNoRows {
+		return accounts.ErrCurrencyMismatch
+	}
+	if err != nil {
+		return err
+	}
+	return conditionErr
+}
+
+// Transfer moves money between the balances two accounts hold in the amount currency within a single DB transaction
+func (r AccountsRepository) Transfer(ctx context.Context, key vos.IdempotencyKey, from, to vos.AccountID, amount vos.Money) (_, _ entities.Entry, err error) {
+	const operation = "postgres.AccountsRepository.Transfer"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	var rawDebit, rawCredit sqlc.Entry
+	err = r.inTx(ctx, func(q *sqlc.Queries) error {
+		// rows are always locked in the same order so that crossed transfers don't deadlock
+		locked, err := q.LockAccounts(ctx, []string{from.String(), to.String()})
+		if err != nil {
+			return err
+		}
+		if len(locked) != 2 {
+			return accounts.ErrAccountNotFound
+		}
+
+		debited, err := q.Withdraw(ctx, sqlc.WithdrawParams{
+			AccountID: from.String(),
+			Currency:  amount.Currency().String(),
+			Amount:    amount.Int64(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return diagnoseUpdate(ctx, q, from, amount.Currency(), accounts.ErrInsufficientBalance)
+		}
+		if err != nil {
+			return err
+		}
+
+		credited, err := q.Deposit(ctx, sqlc.DepositParams{
+			AccountID: to.String(),
+			Currency:  amount.Currency().String(),
+			Amount:    amount.Int64(),
+		})
+		if err == pgx_errors.ErrNoRows {
+			return diagnoseUpdate(ctx, q, to, amount.Currency(), accounts.ErrAccountNotFound)
+		}
+		if err != nil {
+			return err
+		}
+
+		rawDebit, err = createEntry(ctx, q, entities.Entry{
+			AccountID:      from,
+			CounterpartID:  to,
+			Operation:      entities.OperationTransferOut,
+			Amount:         amount,
+			IdempotencyKey: key,
+		}, balances(debited))
+		if err != nil {
+			return err
+		}
+
+		rawCredit, err = createEntry(ctx, q, entities.Entry{
+			AccountID:     to,
+			CounterpartID: from,
+			Operation:     entities.OperationTransferIn,
+			Amount:        amount,
+		}, balances(credited))
+		return err
+	})
+	if err != nil {
+		return entities.Entry{}, entities.Entry{}, domain.Error(operation, err)
+	}
+
+	return mapRawEntry(rawDebit), mapRawEntry(rawCredit), nil
+}
+
+// ListEntries lists account entries in reverse chronological order
+func (r AccountsRepository) ListEntries(ctx context.Context, accID vos.AccountID, filter entities.StatementFilter) (_ []entities.Entry, err error) {
+	const operation = "postgres.AccountsRepository.ListEntries"
+	ctx, end := tracing.Trace(ctx, operation, &err)
+	defer end()
+
+	params := sqlc.ListEntriesParams{
+		AccountID:       accID.String(),
+		CreatedFrom:     filter.From,
+		CreatedTo:       filter.To,
+		BeforeCreatedAt: filter.Before.CreatedAt,
+		BeforeID:        filter.Before.ID.String(),
+		MaxEntries:      int32(filter.Limit),
+	}
+	if params.CreatedTo.IsZero() {
+		params.CreatedTo = endOfTimes
+	}
+	if filter.Before.IsZero() {
+		params.BeforeCreatedAt = endOfTimes
+		params.BeforeID = uuid.Nil.String()
+	}
+
+	rawEntries, err := r.q.ListEntries(ctx, params)
+	if err != nil {
+		return nil, domain.Error(operation, err)
+	}
+
+	entries := make([]entities.Entry, 0, len(rawEntries))
+	for _, rawEntry := range rawEntries {
+		entries = append(entries, mapRawEntry(rawEntry))
+	}
+
+	return entries, nil
+}
+
+// endOfTimes is later than any entry or account creation time
+var endOfTimes = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// maxUUID sorts after any other UUID
+const maxUUID = "ffffffff-ffff-ffff-ffff-ffffffffffff"
+
+// maxCurrency sorts after any ISO-4217 code
+const maxCurrency vos.Currency = "ZZZZ"
+
+// numericValueOutOfRange is the SQLSTATE of arithmetic overflows
+const numericValueOutOfRange = "22003"
+
+// balances holds the account amounts right after an update
+type balances struct {
+	Balance         int64
+	AvailableCredit int64
+	Held            int64
+}
+
+// balance maps the amounts into a balance of the currency
+func (b balances) balance(currency vos.Currency) entities.Balance {
+	return entities.Balance{
+		Balance:         vos.NewMoney(b.Balance, currency),
+		AvailableCredit: vos.NewMoney(b.AvailableCredit, currency),
+		Held:            vos.NewMoney(b.Held, currency),
+	}
+}
+
+// registerEntry applies a balance update and appends it to the ledger within a single DB transaction
+func (r AccountsRepository) registerEntry(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, op entities.Operation, amount vos.Money, update func(q *sqlc.Queries) (balances, error)) (entities.Entry, error) {
+	var rawEntry sqlc.Entry
+	err := r.inTx(ctx, func(q *sqlc.Queries) error {
+		updated, err := update(q)
+		if err != nil {
+			return err
+		}
+
+		rawEntry, err = createEntry(ctx, q, entities.Entry{
+			AccountID:      accID,
+			Operation:      op,
+			Amount:         amount,
+			IdempotencyKey: key,
+		}, updated)
+		return err
+	})
+	if err != nil {
+		return entities.Entry{}, err
+	}
+
+	return mapRawEntry(rawEntry), nil
+}
+
+// inTx runs fn within a DB transaction, rolling it back if any error is returned
+func (r AccountsRepository) inTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	err := r.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		return fn(sqlc.New(tracedDB{tx}))
+	})
+	if pgerr, ok := err.(*pgconn.PgError); ok {
+		if pgerr.ConstraintName == "entries_idempotency_key_key" || pgerr.ConstraintName == "holds_idempotency_key_key" {
+			return accounts.ErrDuplicatedIdempotencyKey
+		}
+		// balances are bigint, moving amounts that would take them past its range fails rather than wrapping around
+		if pgerr.Code == numericValueOutOfRange {
+			return accounts.ErrAmountOutOfRange
+		}
+	}
+	return err
+}
+
+// createEntry appends an entry to the ledger along with the event it triggers
+func createEntry(ctx context.Context, q *sqlc.Queries, entry entities.Entry, updated balances) (sqlc.Entry, error) {
+	rawEntry, err := q.CreateEntry(ctx, sqlc.CreateEntryParams{
+		AccountID:       entry.AccountID.String(),
+		Operation:       entry.Operation.String(),
+		Currency:        entry.Amount.Currency().String(),
+		Amount:          entry.Amount.Int64(),
+		Balance:         updated.Balance,
+		AvailableCredit: updated.AvailableCredit,
+		Held:            updated.Held,
+		IdempotencyKey: sql.NullString{
+			String: entry.IdempotencyKey.String(),
+			Valid:  entry.IdempotencyKey != "",
+		},
+		CounterpartID: nullUUID(entry.CounterpartID.String()),
+	})
+	if err != nil {
+		return sqlc.Entry{}, err
+	}
 
-	return nil
+	return rawEntry, appendEvent(ctx, q, entities.NewEntryEvent(mapRawEntry(rawEntry)))
+}
+
+func nullUUID(raw string) uuid.NullUUID {
+	id, err := uuid.Parse(raw)
+	return uuid.NullUUID{
+		UUID:  id,
+		Valid: err == nil,
+	}
+}
+
+// withBalances maps an account along with the balances it holds
+func withBalances(ctx context.Context, q *sqlc.Queries, rawAcc sqlc.Account) (entities.Account, error) {
+	accs, err := withAllBalances(ctx, q, []sqlc.Account{rawAcc})
+	if err != nil {
+		return entities.Account{}, err
+	}
+	return accs[0], nil
+}
+
+// withAllBalances maps accounts along with the balances they hold, fetched all at once
+func withAllBalances(ctx context.Context, q *sqlc.Queries, rawAccs []sqlc.Account) ([]entities.Account, error) {
+	ids := make([]string, 0, len(rawAccs))
+	for _, rawAcc := range rawAccs {
+		ids = append(ids, rawAcc.ID)
+	}
+
+	rawBalances, err := q.ListBalances(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	held := make(map[string][]entities.Balance, len(rawAccs))
+	for _, rawBalance := range rawBalances {
+		held[rawBalance.AccountID] = append(held[rawBalance.AccountID], mapRawBalance(rawBalance))
+	}
+
+	accs := make([]entities.Account, 0, len(rawAccs))
+	for _, rawAcc := range rawAccs {
+		acc := mapRawAccount(rawAcc)
+		acc.Balances = held[rawAcc.ID]
+		accs = append(accs, acc)
+	}
+
+	return accs, nil
 }
 
 func mapRawAccount(rawAcc sqlc.Account) entities.Account {
 	return entities.Account{
-		ID:              vos.AccountID(rawAcc.ID),
-		Document:        vos.Document(rawAcc.Document),
-		Balance:         vos.Money(rawAcc.Balance),
-		AvailableCredit: vos.Money(rawAcc.AvailableCredit),
-		CreatedAt:       rawAcc.CreatedAt,
-		UpdateAt:        rawAcc.UpdatedAt,
+		ID:           vos.AccountID(rawAcc.ID),
+		Document:     vos.Document(rawAcc.Document),
+		DocumentType: vos.DocumentType(rawAcc.DocumentType.String),
+		Currency:     vos.Currency(rawAcc.Currency),
+		Status:       entities.AccountStatus(rawAcc.Status),
+		CreatedAt:    rawAcc.CreatedAt,
+		UpdateAt:     rawAcc.UpdatedAt,
 	}
 }
+
+func mapRawBalance(rawBalance sqlc.Balance) entities.Balance {
+	currency := vos.Currency(rawBalance.Currency)
+	return entities.Balance{
+		Balance:         vos.NewMoney(rawBalance.Balance, currency),
+		Held:            vos.NewMoney(rawBalance.Held, currency),
+		CreditLimit:     vos.NewMoney(rawBalance.CreditLimit, currency),
+		AvailableCredit: vos.NewMoney(rawBalance.AvailableCredit, currency),
+	}
+}
+
+func mapRawEntry(rawEntry sqlc.Entry) entities.Entry {
+	currency := vos.Currency(rawEntry.Currency)
+	entry := entities.Entry{
+		ID:              vos.TransactionID(rawEntry.ID),
+		AccountID:       vos.AccountID(rawEntry.AccountID),
+		Operation:       entities.Operation(rawEntry.Operation),
+		Amount:          vos.NewMoney(rawEntry.Amount, currency),
+		Balance:         vos.NewMoney(rawEntry.Balance, currency),
+		AvailableCredit: vos.NewMoney(rawEntry.AvailableCredit, currency),
+		Held:            vos.NewMoney(rawEntry.Held, currency),
+		IdempotencyKey:  vos.IdempotencyKey(rawEntry.IdempotencyKey.String),
+		CreatedAt:       rawEntry.CreatedAt,
+	}
+	if rawEntry.CounterpartID.Valid {
+		entry.CounterpartID = vos.AccountID(rawEntry.CounterpartID.UUID.String())
+	}
+
+	return entry
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+)
+
+// watcherBuffer is how many updates a slow watcher may lag behind before older ones are dropped
+const watcherBuffer = 16
+
+var _ accounts.Broker = &Broker{}
+
+// Broker fans balance updates out to watchers within this process
+type Broker struct {
+	mu       sync.Mutex
+	closed   bool
+	watchers map[vos.AccountID]map[chan entities.BalanceUpdate]struct{}
+}
+
+// NewBroker returns a broker with no watchers
+func NewBroker() *Broker {
+	return &Broker{
+		watchers: make(map[vos.AccountID]map[chan entities.BalanceUpdate]struct{}),
+	}
+}
+
+// Publish hands the update to every watcher of its account without ever blocking.
+// Watchers whose buffer is full lose their oldest update since only the latest balance matters.
+func (b *Broker) Publish(update entities.BalanceUpdate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.watchers[update.AccountID] {
+		select {
+		case ch <- update:
+			continue
+		default:
+		}
+
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- update
+	}
+}
+
+// Subscribe starts watching an account, updates are delivered until unsubscribe is called
+func (b *Broker) Subscribe(accID vos.AccountID) (<-chan entities.BalanceUpdate, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan entities.BalanceUpdate, watcherBuffer)
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if b.watchers[accID] == nil {
+		b.watchers[accID] = make(map[chan entities.BalanceUpdate]struct{})
+	}
+	b.watchers[accID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { b.unsubscribe(accID, ch) })
+	}
+}
+
+// Close ends every watch, closing their channels, and refuses new ones
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for accID, watchers := range b.watchers {
+		for ch := range watchers {
+			close(ch)
+		}
+		delete(b.watchers, accID)
+	}
+}
+
+func (b *Broker) unsubscribe(accID vos.AccountID, ch chan entities.BalanceUpdate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	watchers, ok := b.watchers[accID]
+	if !ok {
+		return
+	}
+	if _, ok := watchers[ch]; !ok {
+		// already closed by Close
+		return
+	}
+
+	delete(watchers, ch)
+	close(ch)
+	if len(watchers) == 0 {
+		delete(b.watchers, accID)
+	}
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/fernandodr19/mybank-acc/pkg/config"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// Publisher delivers events to downstream services.
+// An event is only considered published once Publish returns without error.
+type Publisher interface {
+	Publish(ctx context.Context, event entities.Event) error
+}
+
+// NewPublisher builds the publisher picked on config
+func NewPublisher(cfg config.Outbox) (Publisher, error) {
+	switch cfg.Publisher {
+	case "log":
+		return LogPublisher{}, nil
+	case "file":
+		return NewFilePublisher(cfg.File)
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", cfg.Publisher)
+	}
+}
+
+var (
+	_ Publisher = LogPublisher{}
+	_ Publisher = &FilePublisher{}
+	_ Publisher = &MemoryPublisher{}
+)
+
+// LogPublisher logs events, handy for local development
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(ctx context.Context, event entities.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	logger.FromCtx(ctx).WithFields(logrus.Fields{
+		"eventID":   event.ID,
+		"eventType": event.Type,
+		"accID":     event.AccountID,
+		"data":      string(data),
+	}).Infoln("event published")
+
+	return nil
+}
+
+// FilePublisher appends events as JSON lines to a file
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFilePublisher opens (or creates) the file events are appended to
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	return &FilePublisher{file: file}, nil
+}
+
+func (p *FilePublisher) Publish(ctx context.Context, event entities.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+
+	// events must be durable before the outbox marks them as published
+	return p.file.Sync()
+}
+
+// Close closes the events file
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
+
+// MemoryPublisher keeps published events in memory, meant for tests
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []entities.Event
+}
+
+// NewMemoryPublisher returns an empty in memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event entities.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+	return nil
+}
+
+// Events returns the events published for an account in publishing order
+func (p *MemoryPublisher) Events(accID vos.AccountID) []entities.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var events []entities.Event
+	for _, event := range p.events {
+		if event.AccountID == accID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Reset forgets every published event
+func (p *MemoryPublisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = nil
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/config"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+)
+
+// Outbox holds events written along with account changes until they get published
+type Outbox interface {
+	RelayEvents(ctx context.Context, limit int, publish func(context.Context, entities.Event) error) (int, error)
+}
+
+// Relay polls the outbox publishing pending events.
+// Events are published at least once: those whose publication can't be confirmed are retried on the next poll.
+type Relay struct {
+	outbox    Outbox
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+}
+
+// NewRelay builds an outbox relay
+func NewRelay(outbox Outbox, publisher Publisher, cfg config.Outbox) *Relay {
+	return &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		interval:  cfg.PollInterval,
+		batchSize: cfg.BatchSize,
+	}
+}
+
+// Run relays events until ctx is done, closing the publisher afterwards if it is closable
+func (r Relay) Run(ctx context.Context) {
+	log := logger.FromCtx(ctx)
+	log.WithField("interval", r.interval.String()).Infoln("outbox relay starting...")
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			if closer, ok := r.publisher.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					log.WithError(err).Errorln("failed closing events publisher")
+				}
+			}
+			log.Infoln("outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain publishes batches of events while they keep coming full
+func (r Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := r.outbox.RelayEvents(ctx, r.batchSize, r.publisher.Publish)
+		if err != nil {
+			logger.FromCtx(ctx).WithError(err).Errorln("failed relaying events")
+			return
+		}
+		if published < r.batchSize {
+			return
+		}
+	}
+}
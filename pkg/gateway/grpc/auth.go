@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// authInterceptor only lets through calls bearing a valid token granted with the accounts scope
+func authInterceptor(verifier *auth.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, verifier)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor is the streaming counterpart of authInterceptor
+func authStreamInterceptor(verifier *auth.Verifier) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), verifier)
+		if err != nil {
+			return err
+		}
+		return handler(srv, ctxStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticate verifies the call token, returning a context with the caller identity
+func authenticate(ctx context.Context, verifier *auth.Verifier) (context.Context, error) {
+	log := logger.FromCtx(ctx)
+
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	token, err := auth.BearerToken(header)
+	if err != nil {
+		log.WithError(err).Warnln("unauthenticated call")
+		return ctx, ErrUnauthenticated
+	}
+
+	id, err := verifier.Authorize(token, auth.ScopeAccounts)
+	if errors.Is(err, auth.ErrInsufficientScope) {
+		log.WithError(err).Warnln("unauthorized call")
+		return ctx, ErrPermissionDenied
+	}
+	if err != nil {
+		log.WithError(err).Warnln("unauthenticated call")
+		return ctx, ErrUnauthenticated
+	}
+
+	ctx = auth.ToCtx(ctx, id)
+	ctx = logger.ToCtx(ctx, logger.FromCtx(ctx).WithField("caller", id.Subject))
+
+	return ctx, nil
+}
@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	usecase "github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/grpc/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of every google.rpc.ErrorInfo detail sent by this service
+const ErrorDomain = "mybank-acc"
+
+// Stable reason codes sent on google.rpc.ErrorInfo details, clients are meant to rely on them
+const (
+	ReasonAccountNotFound      = "ACCOUNT_NOT_FOUND"
+	ReasonAccountConflict      = "ACCOUNT_CONFLICT"
+	ReasonAccountNotActive     = "ACCOUNT_NOT_ACTIVE"
+	ReasonInvalidAccID         = "INVALID_ACCOUNT_ID"
+	ReasonInvalidDocument      = "INVALID_DOCUMENT"
+	ReasonInvalidCreditLimit   = "INVALID_CREDIT_LIMIT"
+	ReasonInvalidAmount        = "INVALID_AMOUNT"
+	ReasonAmountOutOfRange     = "AMOUNT_OUT_OF_RANGE"
+	ReasonInsufficientBalance  = "INSUFFICIENT_BALANCE"
+	ReasonInsufficientCredit   = "INSUFFICIENT_CREDIT"
+	ReasonCreditLimitExceeded  = "CREDIT_LIMIT_EXCEEDED"
+	ReasonSameAccountTransfer  = "SAME_ACCOUNT_TRANSFER"
+	ReasonInvalidCurrency      = "INVALID_CURRENCY"
+	ReasonCurrencyMismatch     = "CURRENCY_MISMATCH"
+	ReasonIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	ReasonInvalidHoldID        = "INVALID_HOLD_ID"
+	ReasonHoldNotFound         = "HOLD_NOT_FOUND"
+	ReasonHoldNotActive        = "HOLD_NOT_ACTIVE"
+	ReasonCaptureExceedsHold   = "CAPTURE_EXCEEDS_HOLD"
+	ReasonUnauthenticated      = "UNAUTHENTICATED"
+	ReasonPermissionDenied     = "PERMISSION_DENIED"
+	ReasonShuttingDown         = "SHUTTING_DOWN"
+	ReasonUnknown              = "UNKNOWN"
+)
+
+var (
+	ErrAcountNotFound       = newError(codes.NotFound, ReasonAccountNotFound)
+	ErrAccountConflict      = newError(codes.AlreadyExists, ReasonAccountConflict)
+	ErrInvalidCreditLimit   = newError(codes.InvalidArgument, ReasonInvalidCreditLimit, fieldViolation("creditLimit", "must not be negative"))
+	ErrAccountNotActive     = newError(codes.FailedPrecondition, ReasonAccountNotActive)
+	ErrInvalidAmount        = newError(codes.InvalidArgument, ReasonInvalidAmount, fieldViolation("amount", "must be positive"))
+	ErrAmountOutOfRange     = newError(codes.OutOfRange, ReasonAmountOutOfRange, fieldViolation("amount", "must keep balances within 64-bit range"))
+	ErrInsufficientBalance  = newError(codes.InvalidArgument, ReasonInsufficientBalance)
+	ErrInsufficientCredit   = newError(codes.InvalidArgument, ReasonInsufficientCredit)
+	ErrCreditLimitExceeded  = newError(codes.InvalidArgument, ReasonCreditLimitExceeded)
+	ErrSameAccountTransfer  = newError(codes.InvalidArgument, ReasonSameAccountTransfer, fieldViolation("toAccountID", "must differ from fromAccountID"))
+	ErrInvalidCurrency      = newError(codes.InvalidArgument, ReasonInvalidCurrency, fieldViolation("currency", "must be a supported ISO-4217 code"))
+	ErrCurrencyMismatch     = newError(codes.FailedPrecondition, ReasonCurrencyMismatch, fieldViolation("currency", "must be one the account holds a balance in"))
+	ErrIdempotencyKeyReused = newError(codes.AlreadyExists, ReasonIdempotencyKeyReused, fieldViolation("idempotencyKey", "already used by a different request"))
+	ErrInvalidAccID         = newError(codes.InvalidArgument, ReasonInvalidAccID, fieldViolation("accountID", "must be an UUID"))
+	ErrInvalidHoldID        = newError(codes.InvalidArgument, ReasonInvalidHoldID, fieldViolation("holdID", "must be an UUID"))
+	ErrHoldNotFound         = newError(codes.NotFound, ReasonHoldNotFound)
+	ErrHoldNotActive        = newError(codes.FailedPrecondition, ReasonHoldNotActive)
+	ErrCaptureExceedsHold   = newError(codes.InvalidArgument, ReasonCaptureExceedsHold, fieldViolation("amount", "must not exceed the held amount"))
+	ErrUnauthenticated      = newError(codes.Unauthenticated, ReasonUnauthenticated)
+	ErrPermissionDenied     = newError(codes.PermissionDenied, ReasonPermissionDenied)
+	ErrShuttingDown         = newError(codes.Unavailable, ReasonShuttingDown)
+	ErrUnknown              = newError(codes.Unknown, ReasonUnknown)
+)
+
+// errorResponse maps response error
+func errorResponse(ctx context.Context, err error) error {
+	logger.FromCtx(ctx).Errorln(err)
+	switch {
+	case errors.Is(err, usecase.ErrAccountNotFound):
+		return ErrAcountNotFound
+	case errors.Is(err, usecase.ErrAccountConflict):
+		return ErrAccountConflict
+	case errors.Is(err, usecase.ErrInvalidDocument):
+		return invalidDocument(err)
+	case errors.Is(err, usecase.ErrInvalidCreditLimit):
+		return ErrInvalidCreditLimit
+	case errors.Is(err, usecase.ErrAccountNotActive):
+		return ErrAccountNotActive
+	case errors.Is(err, usecase.ErrInvalidAmount):
+		return ErrInvalidAmount
+	case errors.Is(err, usecase.ErrAmountOutOfRange):
+		return ErrAmountOutOfRange
+	case errors.Is(err, usecase.ErrInsufficientBalance):
+		return ErrInsufficientBalance
+	case errors.Is(err, usecase.ErrInsufficientCredit):
+		return ErrInsufficientCredit
+	case errors.Is(err, usecase.ErrCreditLimitExceeded):
+		return ErrCreditLimitExceeded
+	case errors.Is(err, usecase.ErrSameAccountTransfer):
+		return ErrSameAccountTransfer
+	case errors.Is(err, usecase.ErrInvalidCurrency):
+		return ErrInvalidCurrency
+	case errors.Is(err, usecase.ErrCurrencyMismatch):
+		return ErrCurrencyMismatch
+	case errors.Is(err, usecase.ErrIdempotencyKeyReused):
+		return ErrIdempotencyKeyReused
+	case errors.Is(err, usecase.ErrHoldNotFound):
+		return ErrHoldNotFound
+	case errors.Is(err, usecase.ErrHoldNotActive):
+		return ErrHoldNotActive
+	case errors.Is(err, usecase.ErrCaptureExceedsHold):
+		return ErrCaptureExceedsHold
+	default:
+		return ErrUnknown
+	}
+}
+
+// invalidDocument tells precisely why the document was refused
+func invalidDocument(err error) error {
+	description := "must be a valid CPF or CNPJ"
+	for _, reason := range []error{
+		vos.ErrDocumentFormat,
+		vos.ErrDocumentLength,
+		vos.ErrDocumentRepeatedDigits,
+		vos.ErrDocumentCheckDigits,
+	} {
+		if errors.Is(err, reason) {
+			description = reason.Error()
+			break
+		}
+	}
+
+	return newError(codes.InvalidArgument, ReasonInvalidDocument, fieldViolation("documentNumber", description))
+}
+
+// newError builds a status carrying an ErrorInfo with the reason code, plus a BadRequest for field violations,
+// and a failed Response. Its message keeps the legacy "err::<reason>" format.
+func newError(code codes.Code, reason string, violations ...*errdetails.BadRequest_FieldViolation) error {
+	st := status.New(code, "err::"+strings.ToLower(reason))
+
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: ErrorDomain,
+	}, &accounts.Response{
+		ErrorCode:        int32(code),
+		ErrorDescription: st.Message(),
+		ErrorReason:      reason,
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	if len(violations) > 0 {
+		withDetails, err = withDetails.WithDetails(&errdetails.BadRequest{
+			FieldViolations: violations,
+		})
+		if err != nil {
+			return st.Err()
+		}
+	}
+
+	return withDetails.Err()
+}
+
+func fieldViolation(field, description string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	}
+}
@@ -2,23 +2,35 @@ package grpc
 
 import (
 	"context"
-	"errors"
 
 	app "github.com/fernandodr19/mybank-acc/pkg"
-	usecase "github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/config"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/grpc/accounts"
 	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Usecase interface for accoutns usecases
 type Usecase interface {
-	Deposit(ctx context.Context, accID vos.AccountID, amount vos.Money) error
-	Withdraw(ctx context.Context, accID vos.AccountID, amount vos.Money) error
-	ReserveCreditLimit(ctx context.Context, accID vos.AccountID, amount vos.Money) error
+	CreateAccount(ctx context.Context, doc vos.Document, creditLimit vos.Money, currencies ...vos.Currency) (vos.AccountID, error)
+	GetAccountByID(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+	GetAccountByDocument(ctx context.Context, doc vos.Document) (entities.Account, error)
+	Deposit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error)
+	Withdraw(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error)
+	ReserveCreditLimit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error)
+	ReleaseCreditLimit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error)
+	Transfer(ctx context.Context, key vos.IdempotencyKey, from, to vos.AccountID, amount vos.Money) (entities.Entry, error)
+	PlaceHold(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Hold, error)
+	CaptureHold(ctx context.Context, key vos.IdempotencyKey, holdID vos.HoldID, amount vos.Money) (entities.Entry, error)
+	VoidHold(ctx context.Context, holdID vos.HoldID) (entities.Hold, error)
+	WatchAccount(ctx context.Context, accID vos.AccountID) (entities.Account, <-chan entities.BalanceUpdate, error)
 }
 
 // Server grpc
@@ -27,64 +39,275 @@ type Server struct {
 	accounts.UnimplementedAccountsServiceServer
 }
 
-// BuildHandler builds grpc handler
-func BuildHandler(app *app.App) *grpc.Server {
+// BuildHandler builds grpc handler, serving TLS whenever certificates are configured
+func BuildHandler(app *app.App, verifier *auth.Verifier, cfg config.GRPC) (*grpc.Server, error) {
 	s := Server{
 		Usecase: app.Accounts,
 	}
-	grpcServer := grpc.NewServer()
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			otelgrpc.UnaryServerInterceptor(),
+			metricsInterceptor,
+			requestIDInterceptor,
+			authInterceptor(verifier),
+		),
+		grpc.ChainStreamInterceptor(
+			otelgrpc.StreamServerInterceptor(),
+			metricsStreamInterceptor,
+			requestIDStreamInterceptor,
+			authStreamInterceptor(verifier),
+		),
+	}
+
+	if cfg.TLSEnabled() {
+		creds, err := serverCredentials(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	} else {
+		logger.Default().Warnln("gRPC TLS disabled, serving plaintext")
+	}
+
+	grpcServer := grpc.NewServer(opts...)
 	accounts.RegisterAccountsServiceServer(grpcServer, &s)
-	return grpcServer
+	return grpcServer, nil
+}
+
+// newResponse responds a money movement with the account amounts right after it
+func newResponse(entry entities.Entry) *accounts.Response {
+	return &accounts.Response{
+		Success:         true,
+		TransactionID:   entry.ID.String(),
+		Balance:         entry.Balance.Int64(),
+		AvailableCredit: entry.AvailableCredit.Int64(),
+		Currency:        entry.Amount.Currency().String(),
+	}
+}
+
+// CreateAccount handles account creation requests, responding with the created account
+func (s *Server) CreateAccount(ctx context.Context, req *accounts.CreateAccountRequest) (*accounts.Account, error) {
+	currencies := make([]vos.Currency, 0, len(req.Currencies))
+	for _, currency := range req.Currencies {
+		currencies = append(currencies, vos.Currency(currency))
+	}
+
+	creditLimit := vos.NewMoney(req.CreditLimit, vos.Currency(req.Currency))
+	accID, err := s.Usecase.CreateAccount(ctx, vos.Document(req.DocumentNumber), creditLimit, currencies...)
+	if err != nil {
+		return &accounts.Account{}, errorResponse(ctx, err)
+	}
+
+	acc, err := s.Usecase.GetAccountByID(ctx, accID)
+	if err != nil {
+		return &accounts.Account{}, errorResponse(ctx, err)
+	}
+	return newAccount(acc), nil
+}
+
+// GetAccount handles account retrieval requests
+func (s *Server) GetAccount(ctx context.Context, req *accounts.GetAccountRequest) (*accounts.Account, error) {
+	accID, err := uuid.Parse(req.AccountID)
+	if err != nil {
+		return &accounts.Account{}, ErrInvalidAccID
+	}
+
+	acc, err := s.Usecase.GetAccountByID(ctx, vos.AccountID(accID.String()))
+	if err != nil {
+		return &accounts.Account{}, errorResponse(ctx, err)
+	}
+	return newAccount(acc), nil
+}
+
+// GetAccountByDocument handles account retrieval requests by owner document
+func (s *Server) GetAccountByDocument(ctx context.Context, req *accounts.GetAccountByDocumentRequest) (*accounts.Account, error) {
+	acc, err := s.Usecase.GetAccountByDocument(ctx, vos.Document(req.DocumentNumber))
+	if err != nil {
+		return &accounts.Account{}, errorResponse(ctx, err)
+	}
+	return newAccount(acc), nil
+}
+
+func newAccount(acc entities.Account) *accounts.Account {
+	main := acc.MainBalance()
+	resp := &accounts.Account{
+		AccountID:        acc.ID.String(),
+		DocumentNumber:   acc.Document.String(),
+		DocumentType:     acc.DocumentType.String(),
+		Currency:         acc.Currency.String(),
+		Balance:          main.Balance.Int64(),
+		CreditLimit:      main.CreditLimit.Int64(),
+		AvailableCredit:  main.AvailableCredit.Int64(),
+		AvailableBalance: main.AvailableBalance().Int64(),
+		Balances:         make([]*accounts.Balance, 0, len(acc.Balances)),
+		Status:           acc.Status.String(),
+		CreatedAt:        timestamppb.New(acc.CreatedAt),
+		UpdatedAt:        timestamppb.New(acc.UpdateAt),
+	}
+	for _, balance := range acc.Balances {
+		resp.Balances = append(resp.Balances, &accounts.Balance{
+			Currency:         balance.Currency().String(),
+			Balance:          balance.Balance.Int64(),
+			CreditLimit:      balance.CreditLimit.Int64(),
+			AvailableCredit:  balance.AvailableCredit.Int64(),
+			AvailableBalance: balance.AvailableBalance().Int64(),
+		})
+	}
+
+	return resp
+}
+
+// requestedAmount builds the amount of a money movement request
+func requestedAmount(amount int64, currency string) vos.Money {
+	return vos.NewMoney(amount, vos.Currency(currency))
 }
 
 // Deposit handles deposit requests
 func (s *Server) Deposit(ctx context.Context, req *accounts.Request) (*accounts.Response, error) {
-	err := s.Usecase.Deposit(ctx, vos.AccountID(req.AccountID), vos.Money(req.Amount))
+	entry, err := s.Usecase.Deposit(ctx, vos.IdempotencyKey(req.IdempotencyKey), vos.AccountID(req.AccountID), requestedAmount(req.Amount, req.Currency))
 	if err != nil {
 		return &accounts.Response{}, errorResponse(ctx, err)
 	}
-	return &accounts.Response{}, nil
+	return newResponse(entry), nil
 }
 
 // Withdrawal handles withdrawals requests
 func (s *Server) Withdrawal(ctx context.Context, req *accounts.Request) (*accounts.Response, error) {
-	err := s.Usecase.Withdraw(ctx, vos.AccountID(req.AccountID), vos.Money(req.Amount))
+	entry, err := s.Usecase.Withdraw(ctx, vos.IdempotencyKey(req.IdempotencyKey), vos.AccountID(req.AccountID), requestedAmount(req.Amount, req.Currency))
 	if err != nil {
 		return &accounts.Response{}, errorResponse(ctx, err)
 	}
-	return &accounts.Response{}, nil
+	return newResponse(entry), nil
 }
 
 // ReserveCreditLimit handles reserve credit limit requests
 func (s *Server) ReserveCreditLimit(ctx context.Context, req *accounts.Request) (*accounts.Response, error) {
-	err := s.Usecase.ReserveCreditLimit(ctx, vos.AccountID(req.AccountID), vos.Money(req.Amount))
+	entry, err := s.Usecase.ReserveCreditLimit(ctx, vos.IdempotencyKey(req.IdempotencyKey), vos.AccountID(req.AccountID), requestedAmount(req.Amount, req.Currency))
 	if err != nil {
 		return &accounts.Response{}, errorResponse(ctx, err)
 	}
-	return &accounts.Response{}, nil
+	return newResponse(entry), nil
 }
 
-var (
-	ErrAcountNotFound      = status.New(codes.NotFound, "err::account_not_found").Err()
-	ErrInvalidAmount       = status.New(codes.InvalidArgument, "err::invalid_amount").Err()
-	ErrInsufficientBalance = status.New(codes.InvalidArgument, "err::insufficient_balance").Err()
-	ErrInsufficientCredit  = status.New(codes.InvalidArgument, "err::insufficient_credit").Err()
-	ErrUnknown             = status.New(codes.Unknown, "err::unknown").Err()
-)
+// ReleaseCreditLimit handles release credit limit requests
+func (s *Server) ReleaseCreditLimit(ctx context.Context, req *accounts.Request) (*accounts.Response, error) {
+	entry, err := s.Usecase.ReleaseCreditLimit(ctx, vos.IdempotencyKey(req.IdempotencyKey), vos.AccountID(req.AccountID), requestedAmount(req.Amount, req.Currency))
+	if err != nil {
+		return &accounts.Response{}, errorResponse(ctx, err)
+	}
+	return newResponse(entry), nil
+}
+
+// Transfer handles transfer requests, responding with the amounts of the debited account
+func (s *Server) Transfer(ctx context.Context, req *accounts.TransferRequest) (*accounts.Response, error) {
+	entry, err := s.Usecase.Transfer(ctx, vos.IdempotencyKey(req.IdempotencyKey), vos.AccountID(req.FromAccountID), vos.AccountID(req.ToAccountID), requestedAmount(req.Amount, req.Currency))
+	if err != nil {
+		return &accounts.Response{}, errorResponse(ctx, err)
+	}
+	return newResponse(entry), nil
+}
+
+// PlaceHold handles hold requests, responding with the placed hold
+func (s *Server) PlaceHold(ctx context.Context, req *accounts.PlaceHoldRequest) (*accounts.Hold, error) {
+	hold, err := s.Usecase.PlaceHold(ctx, vos.IdempotencyKey(req.IdempotencyKey), vos.AccountID(req.AccountID), requestedAmount(req.Amount, req.Currency))
+	if err != nil {
+		return &accounts.Hold{}, errorResponse(ctx, err)
+	}
+	return newHold(hold), nil
+}
+
+// CaptureHold handles hold capture requests, responding with the withdrawal it turned into
+func (s *Server) CaptureHold(ctx context.Context, req *accounts.CaptureHoldRequest) (*accounts.Response, error) {
+	holdID, err := uuid.Parse(req.HoldID)
+	if err != nil {
+		return &accounts.Response{}, ErrInvalidHoldID
+	}
+
+	entry, err := s.Usecase.CaptureHold(ctx, vos.IdempotencyKey(req.IdempotencyKey), vos.HoldID(holdID.String()), requestedAmount(req.Amount, req.Currency))
+	if err != nil {
+		return &accounts.Response{}, errorResponse(ctx, err)
+	}
+	return newResponse(entry), nil
+}
+
+// VoidHold handles hold void requests, responding with the voided hold
+func (s *Server) VoidHold(ctx context.Context, req *accounts.VoidHoldRequest) (*accounts.Hold, error) {
+	holdID, err := uuid.Parse(req.HoldID)
+	if err != nil {
+		return &accounts.Hold{}, ErrInvalidHoldID
+	}
+
+	hold, err := s.Usecase.VoidHold(ctx, vos.HoldID(holdID.String()))
+	if err != nil {
+		return &accounts.Hold{}, errorResponse(ctx, err)
+	}
+	return newHold(hold), nil
+}
+
+func newHold(hold entities.Hold) *accounts.Hold {
+	return &accounts.Hold{
+		HoldID:         hold.ID.String(),
+		AccountID:      hold.AccountID.String(),
+		Amount:         hold.Amount.Int64(),
+		Currency:       hold.Amount.Currency().String(),
+		CapturedAmount: hold.CapturedAmount.Int64(),
+		Status:         hold.Status.String(),
+		EntryID:        hold.EntryID.String(),
+		ExpiresAt:      timestamppb.New(hold.ExpiresAt),
+		CreatedAt:      timestamppb.New(hold.CreatedAt),
+	}
+}
+
+// WatchAccount streams the account balances, starting with the current ones, every time a movement succeeds.
+// The stream lasts until the client cancels it or the server shuts down.
+func (s *Server) WatchAccount(req *accounts.WatchAccountRequest, stream accounts.AccountsService_WatchAccountServer) error {
+	ctx := stream.Context()
+
+	accID, err := uuid.Parse(req.AccountID)
+	if err != nil {
+		return ErrInvalidAccID
+	}
+
+	acc, updates, err := s.Usecase.WatchAccount(ctx, vos.AccountID(accID.String()))
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	for _, balance := range acc.Balances {
+		err = stream.Send(&accounts.BalanceUpdate{
+			AccountID:        acc.ID.String(),
+			Currency:         balance.Currency().String(),
+			Balance:          balance.Balance.Int64(),
+			AvailableCredit:  balance.AvailableCredit.Int64(),
+			Held:             balance.Held.Int64(),
+			AvailableBalance: balance.AvailableBalance().Int64(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for update := range updates {
+		err = stream.Send(&accounts.BalanceUpdate{
+			AccountID:        update.AccountID.String(),
+			Currency:         update.Balance.Currency().String(),
+			Balance:          update.Balance.Int64(),
+			AvailableCredit:  update.AvailableCredit.Int64(),
+			Held:             update.Held.Int64(),
+			AvailableBalance: update.AvailableBalance().Int64(),
+			Operation:        update.Operation.String(),
+			EntryID:          update.EntryID.String(),
+			HoldID:           update.HoldID.String(),
+			HoldStatus:       update.HoldStatus.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
 
-// errorResponse maps response error
-func errorResponse(ctx context.Context, err error) error {
-	logger.FromCtx(ctx).Errorln(err)
-	switch {
-	case errors.Is(err, usecase.ErrAccountNotFound):
-		return ErrAcountNotFound
-	case errors.Is(err, usecase.ErrInvalidAmount):
-		return ErrInvalidAmount
-	case errors.Is(err, usecase.ErrInsufficientBalance):
-		return ErrInsufficientBalance
-	case errors.Is(err, usecase.ErrInsufficientCredit):
-		return ErrInsufficientCredit
-	default:
-		return ErrUnknown
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
 	}
+	return ErrShuttingDown
 }
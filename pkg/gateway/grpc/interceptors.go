@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/metrics"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// XRequestID is the metadata key correlating gRPC calls
+const XRequestID = "x-request-id"
+
+// requestIDInterceptor creates a request id if none is provided, echoing it back on response headers,
+// inserts a logger with it on context and logs every handled call
+func requestIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	ctx, log := withRequestID(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	logHandled(log, err, start)
+
+	return resp, err
+}
+
+// requestIDStreamInterceptor is the streaming counterpart of requestIDInterceptor
+func requestIDStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	ctx, log := withRequestID(stream.Context(), info.FullMethod)
+	err := handler(srv, ctxStream{ServerStream: stream, ctx: ctx})
+	logHandled(log, err, start)
+
+	return err
+}
+
+func withRequestID(ctx context.Context, method string) (context.Context, *logrus.Entry) {
+	var reqID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(XRequestID); len(values) > 0 {
+			reqID = values[0]
+		}
+	}
+	if reqID == "" {
+		reqID = uuid.NewString()
+	}
+
+	log := logger.Default().WithFields(logrus.Fields{
+		XRequestID: reqID,
+		"method":   method,
+	})
+
+	err := grpc.SetHeader(ctx, metadata.Pairs(XRequestID, reqID))
+	if err != nil {
+		log.WithError(err).Warnln("failed setting request id header")
+	}
+
+	return logger.ToCtx(ctx, log), log
+}
+
+func logHandled(log *logrus.Entry, err error, start time.Time) {
+	log.WithFields(logrus.Fields{
+		"code":     status.Code(err).String(),
+		"duration": time.Since(start).String(),
+	}).Infoln("gRPC call handled")
+}
+
+// metricsInterceptor records count and latency of calls by method and status code
+func metricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	metrics.ObserveGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+	return resp, err
+}
+
+// metricsStreamInterceptor records count and lifetime of streams by method and status code
+func metricsStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, stream)
+
+	metrics.ObserveGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+	return err
+}
+
+// ctxStream overrides the context of a stream so that interceptors can enrich it
+type ctxStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s ctxStream) Context() context.Context {
+	return s.ctx
+}
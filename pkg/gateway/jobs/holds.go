@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/config"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+)
+
+// HoldsExpirer releases holds past their expiration
+type HoldsExpirer interface {
+	ExpireHolds(ctx context.Context, limit int) (int, error)
+}
+
+// HoldsSweeper periodically expires holds so that their funds get back to the available balance.
+// Sweepers of several instances can run along, each hold being expired by a single one.
+type HoldsSweeper struct {
+	expirer   HoldsExpirer
+	interval  time.Duration
+	batchSize int
+}
+
+// NewHoldsSweeper builds a holds sweeper
+func NewHoldsSweeper(expirer HoldsExpirer, cfg config.Holds) *HoldsSweeper {
+	return &HoldsSweeper{
+		expirer:   expirer,
+		interval:  cfg.SweepInterval,
+		batchSize: cfg.BatchSize,
+	}
+}
+
+// Run expires holds until ctx is done
+func (s HoldsSweeper) Run(ctx context.Context) {
+	log := logger.FromCtx(ctx)
+	log.WithField("interval", s.interval.String()).Infoln("holds sweeper starting...")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Infoln("holds sweeper stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep expires batches of holds while they keep coming full
+func (s HoldsSweeper) sweep(ctx context.Context) {
+	for ctx.Err() == nil {
+		expired, err := s.expirer.ExpireHolds(ctx, s.batchSize)
+		if err != nil {
+			logger.FromCtx(ctx).WithError(err).Errorln("failed expiring holds")
+			return
+		}
+		if expired < s.batchSize {
+			return
+		}
+	}
+}
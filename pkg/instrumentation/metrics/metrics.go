@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "mybank_acc"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "REST requests handled by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "REST request latency by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "gRPC calls handled by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC call latency by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	movements = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "accounts",
+		Name:      "movements_total",
+		Help:      "Movements registered on the ledger by operation and currency.",
+	}, []string{"operation", "currency"})
+
+	movementsAmount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "accounts",
+		Name:      "movements_amount_total",
+		Help:      "Sum of the amounts (in minor units of the currency) registered on the ledger by operation and currency.",
+	}, []string{"operation", "currency"})
+
+	usecaseErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "accounts",
+		Name:      "usecase_errors_total",
+		Help:      "Usecase failures by operation and domain error.",
+	}, []string{"operation", "error"})
+)
+
+// ObserveHTTPRequest records a handled REST request
+func ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(route, method, code).Inc()
+	httpDuration.WithLabelValues(route, method, code).Observe(duration.Seconds())
+}
+
+// ObserveGRPCRequest records a handled gRPC call
+func ObserveGRPCRequest(method, code string, duration time.Duration) {
+	grpcRequests.WithLabelValues(method, code).Inc()
+	grpcDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+// CountMovement records a movement registered on the ledger
+func CountMovement(operation, currency string, amount int64) {
+	movements.WithLabelValues(operation, currency).Inc()
+	movementsAmount.WithLabelValues(operation, currency).Add(float64(amount))
+}
+
+// CountUsecaseError records a usecase failure
+func CountUsecaseError(operation, err string) {
+	usecaseErrors.WithLabelValues(operation, err).Inc()
+}
@@ -0,0 +1,301 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
+	acc_grpc "github.com/fernandodr19/mybank-acc/pkg/gateway/grpc"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/grpc/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/tests/clients"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Auth_REST(t *testing.T) {
+	testTable := []struct {
+		Name               string
+		Method             string
+		Path               string
+		Authorization      string
+		ExpectedStatusCode int
+	}{
+		{
+			Name:               "unauthorized: missing token",
+			Method:             http.MethodGet,
+			Path:               "/api/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9",
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			Name:               "unauthorized: not a bearer token",
+			Method:             http.MethodGet,
+			Path:               "/api/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Authorization:      "Basic dXNlcjpwYXNz",
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			Name:               "unauthorized: expired token",
+			Method:             http.MethodGet,
+			Path:               "/api/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Authorization:      "Bearer " + signTestToken(-time.Minute, auth.ScopeAccounts),
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			Name:               "unauthorized: token signed with another key",
+			Method:             http.MethodGet,
+			Path:               "/api/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Authorization:      "Bearer " + signForeignToken(t),
+			ExpectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			Name:               "forbidden: public route without accounts scope",
+			Method:             http.MethodGet,
+			Path:               "/api/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Authorization:      "Bearer " + signTestToken(time.Hour, auth.ScopeAdmin),
+			ExpectedStatusCode: http.StatusForbidden,
+		},
+		{
+			Name:               "forbidden: admin route without admin scope",
+			Method:             http.MethodPost,
+			Path:               "/admin/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9/block",
+			Authorization:      "Bearer " + signTestToken(time.Hour, auth.ScopeAccounts),
+			ExpectedStatusCode: http.StatusForbidden,
+		},
+		{
+			Name:               "authorized public route",
+			Method:             http.MethodGet,
+			Path:               "/api/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Authorization:      "Bearer " + signTestToken(time.Hour, auth.ScopeAccounts),
+			ExpectedStatusCode: http.StatusNotFound,
+		},
+		{
+			Name:               "authorized admin route",
+			Method:             http.MethodPost,
+			Path:               "/admin/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9/block",
+			Authorization:      "Bearer " + signTestToken(time.Hour, auth.ScopeAdmin),
+			ExpectedStatusCode: http.StatusNotFound,
+		},
+		{
+			Name:               "healthcheck is open",
+			Method:             http.MethodGet,
+			Path:               "/healthcheck",
+			ExpectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			// prepare
+			req, err := http.NewRequest(tt.Method, testEnv.Server.URL+tt.Path, nil)
+			require.NoError(t, err)
+			if tt.Authorization != "" {
+				req.Header.Set("Authorization", tt.Authorization)
+			}
+
+			// test
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// assert
+			assert.Equal(t, tt.ExpectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func Test_Auth_GRPC(t *testing.T) {
+	testTable := []struct {
+		Name         string
+		Token        string
+		ExpectedCode codes.Code
+	}{
+		{
+			Name:         "unauthenticated: missing token",
+			ExpectedCode: codes.Unauthenticated,
+		},
+		{
+			Name:         "unauthenticated: expired token",
+			Token:        signTestToken(-time.Minute, auth.ScopeAccounts),
+			ExpectedCode: codes.Unauthenticated,
+		},
+		{
+			Name:         "unauthenticated: token signed with another key",
+			Token:        signForeignToken(t),
+			ExpectedCode: codes.Unauthenticated,
+		},
+		{
+			Name:         "permission denied: missing accounts scope",
+			Token:        signTestToken(time.Hour, auth.ScopeAdmin),
+			ExpectedCode: codes.PermissionDenied,
+		},
+		{
+			Name:         "authorized",
+			Token:        signTestToken(time.Hour, auth.ScopeAccounts),
+			ExpectedCode: codes.NotFound,
+		},
+	}
+
+	creds, err := clients.NewTLSCredentials("localhost", testEnv.Certs.CAFile, testEnv.Certs.ClientCertFile, testEnv.Certs.ClientKeyFile)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(testEnv.GrpcAddr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := accounts.NewAccountsServiceClient(conn)
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			// prepare
+			ctx := context.Background()
+			if tt.Token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+tt.Token)
+			}
+
+			// test
+			_, err := client.Deposit(ctx, &accounts.Request{
+				AccountID: "55c217e7-177b-4289-afe3-d763c2ded6d9",
+				Amount:    10,
+			})
+
+			// assert
+			assert.Equal(t, tt.ExpectedCode, status.Code(err))
+
+			// streams are guarded as well
+			stream, err := client.WatchAccount(ctx, &accounts.WatchAccountRequest{
+				AccountID: "55c217e7-177b-4289-afe3-d763c2ded6d9",
+			})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, tt.ExpectedCode, status.Code(err))
+		})
+	}
+}
+
+// signForeignToken issues a well formed token the test servers must not trust
+func signForeignToken(t *testing.T) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		Scope: string(auth.ScopeAccounts),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "intruder",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte("another-secret"))
+	require.NoError(t, err)
+	return token
+}
+
+func Test_MutualTLS_GRPC(t *testing.T) {
+	foreign, err := generateTestCerts(t.TempDir())
+	require.NoError(t, err)
+
+	tlsCreds := func(caFile, certFile, keyFile string) grpc.DialOption {
+		creds, err := clients.NewTLSCredentials("localhost", caFile, certFile, keyFile)
+		require.NoError(t, err)
+		return grpc.WithTransportCredentials(creds)
+	}
+
+	testTable := []struct {
+		Name         string
+		DialOption   grpc.DialOption
+		ExpectedCode codes.Code
+	}{
+		{
+			Name:         "plaintext connection is refused",
+			DialOption:   grpc.WithInsecure(),
+			ExpectedCode: codes.Unavailable,
+		},
+		{
+			Name:         "client certificate from unknown CA is refused",
+			DialOption:   tlsCreds(testEnv.Certs.CAFile, foreign.ClientCertFile, foreign.ClientKeyFile),
+			ExpectedCode: codes.Unavailable,
+		},
+		{
+			Name:         "server certificate from unknown CA is not trusted",
+			DialOption:   tlsCreds(foreign.CAFile, testEnv.Certs.ClientCertFile, testEnv.Certs.ClientKeyFile),
+			ExpectedCode: codes.Unavailable,
+		},
+		{
+			Name:         "mutual TLS",
+			DialOption:   tlsCreds(testEnv.Certs.CAFile, testEnv.Certs.ClientCertFile, testEnv.Certs.ClientKeyFile),
+			ExpectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			// prepare
+			conn, err := grpc.Dial(testEnv.GrpcAddr, tt.DialOption)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(),
+				"authorization", "Bearer "+signTestToken(time.Hour, auth.ScopeAccounts))
+
+			// test
+			_, err = accounts.NewAccountsServiceClient(conn).Deposit(ctx, &accounts.Request{
+				AccountID: "55c217e7-177b-4289-afe3-d763c2ded6d9",
+				Amount:    10,
+			})
+
+			// assert
+			assert.Equal(t, tt.ExpectedCode, status.Code(err))
+		})
+	}
+}
+
+func Test_RequestID_GRPC(t *testing.T) {
+	testTable := []struct {
+		Name      string
+		RequestID string
+	}{
+		{
+			Name:      "incoming request id is honored",
+			RequestID: "3f1c8a52-5b8e-4c1f-9d0a-8f3e0b6a2c71",
+		},
+		{
+			Name: "request id is created when missing",
+		},
+	}
+
+	creds, err := clients.NewTLSCredentials("localhost", testEnv.Certs.CAFile, testEnv.Certs.ClientCertFile, testEnv.Certs.ClientKeyFile)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(testEnv.GrpcAddr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := accounts.NewAccountsServiceClient(conn)
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			// prepare
+			ctx := metadata.AppendToOutgoingContext(context.Background(),
+				"authorization", "Bearer "+signTestToken(time.Hour, auth.ScopeAccounts))
+			if tt.RequestID != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, acc_grpc.XRequestID, tt.RequestID)
+			}
+
+			// test
+			var header metadata.MD
+			_, err := client.Deposit(ctx, &accounts.Request{
+				AccountID: "55c217e7-177b-4289-afe3-d763c2ded6d9",
+				Amount:    10,
+			}, grpc.Header(&header))
+
+			// assert
+			assert.Equal(t, codes.NotFound, status.Code(err))
+			require.Len(t, header.Get(acc_grpc.XRequestID), 1)
+			reqID := header.Get(acc_grpc.XRequestID)[0]
+			if tt.RequestID != "" {
+				assert.Equal(t, tt.RequestID, reqID)
+				return
+			}
+			_, err = uuid.Parse(reqID)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+)
+
+var _ credentials.PerRPCCredentials = BearerToken("")
+
+// BearerToken sends an access token along with every gRPC call
+type BearerToken string
+
+// GetRequestMetadata sets the authorization header
+func (t BearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + string(t),
+	}, nil
+}
+
+// RequireTransportSecurity prevents tokens from leaking over plaintext connections
+func (t BearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// NewTLSCredentials trusts servers signed by the given CA, presenting the client certificate for mutual TLS
+func NewTLSCredentials(serverName, caFile, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificate found on CA file")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading client certificate: %w", err)
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		ServerName:   serverName,
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
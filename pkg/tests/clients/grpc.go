@@ -4,12 +4,14 @@ import (
 	"context"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
 	usecase "github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	acc_grpc "github.com/fernandodr19/mybank-acc/pkg/gateway/grpc"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/grpc/accounts"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -26,43 +28,271 @@ func NewFakeAccountslient(conn *grpc.ClientConn) *FakeClient {
 
 }
 
+// CreateAccount requests an account creation to the accounts server
+func (c FakeClient) CreateAccount(ctx context.Context, doc vos.Document, creditLimit vos.Money, currencies ...vos.Currency) (entities.Account, error) {
+	const operation = "accounts.Client.CreateAccount"
+	req := &accounts.CreateAccountRequest{
+		DocumentNumber: doc.String(),
+		CreditLimit:    creditLimit.Int64(),
+		Currency:       creditLimit.Currency().String(),
+	}
+	for _, currency := range currencies {
+		req.Currencies = append(req.Currencies, currency.String())
+	}
+
+	acc, err := c.client.CreateAccount(ctx, req)
+	if err != nil {
+		return entities.Account{}, parseServerErr(operation, err)
+	}
+	return parseAccount(acc), nil
+}
+
+// GetAccount requests an account to the accounts server
+func (c FakeClient) GetAccount(ctx context.Context, accID vos.AccountID) (entities.Account, error) {
+	const operation = "accounts.Client.GetAccount"
+	acc, err := c.client.GetAccount(ctx, &accounts.GetAccountRequest{
+		AccountID: accID.String(),
+	})
+	if err != nil {
+		return entities.Account{}, parseServerErr(operation, err)
+	}
+	return parseAccount(acc), nil
+}
+
+// GetAccountByDocument requests an account by its owner document to the accounts server
+func (c FakeClient) GetAccountByDocument(ctx context.Context, doc vos.Document) (entities.Account, error) {
+	const operation = "accounts.Client.GetAccountByDocument"
+	acc, err := c.client.GetAccountByDocument(ctx, &accounts.GetAccountByDocumentRequest{
+		DocumentNumber: doc.String(),
+	})
+	if err != nil {
+		return entities.Account{}, parseServerErr(operation, err)
+	}
+	return parseAccount(acc), nil
+}
+
+func parseAccount(acc *accounts.Account) entities.Account {
+	parsed := entities.Account{
+		ID:           vos.AccountID(acc.AccountID),
+		Document:     vos.Document(acc.DocumentNumber),
+		DocumentType: vos.DocumentType(acc.DocumentType),
+		Currency:     vos.Currency(acc.Currency),
+		Status:       entities.AccountStatus(acc.Status),
+		CreatedAt:    acc.CreatedAt.AsTime(),
+		UpdateAt:     acc.UpdatedAt.AsTime(),
+	}
+	for _, balance := range acc.Balances {
+		currency := vos.Currency(balance.Currency)
+		parsed.Balances = append(parsed.Balances, entities.Balance{
+			Balance:         vos.NewMoney(balance.Balance, currency),
+			CreditLimit:     vos.NewMoney(balance.CreditLimit, currency),
+			AvailableCredit: vos.NewMoney(balance.AvailableCredit, currency),
+			Held:            vos.NewMoney(balance.Balance-balance.AvailableBalance, currency),
+		})
+	}
+
+	return parsed
+}
+
 // Deposit requests a deposit to the accounts server
-func (c FakeClient) Deposit(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+func (c FakeClient) Deposit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error) {
 	const operation = "accounts.Client.Deposit"
-	_, err := c.client.Deposit(ctx, &accounts.Request{
-		AccountID: accID.String(),
-		Amount:    amount.Int64(),
+	resp, err := c.client.Deposit(ctx, &accounts.Request{
+		AccountID:      accID.String(),
+		Amount:         amount.Int64(),
+		Currency:       amount.Currency().String(),
+		IdempotencyKey: key.String(),
 	})
 	if err != nil {
-		return parseServerErr(operation, err)
+		return entities.Entry{}, parseServerErr(operation, err)
 	}
-	return nil
+	return parseEntry(accID, resp), nil
 }
 
 // Withdrawal requests a withdrawal to the accounts server
-func (c FakeClient) Withdrawal(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+func (c FakeClient) Withdrawal(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error) {
 	const operation = "accounts.Client.Withdrawal"
-	_, err := c.client.Withdrawal(ctx, &accounts.Request{
-		AccountID: accID.String(),
-		Amount:    amount.Int64(),
+	resp, err := c.client.Withdrawal(ctx, &accounts.Request{
+		AccountID:      accID.String(),
+		Amount:         amount.Int64(),
+		Currency:       amount.Currency().String(),
+		IdempotencyKey: key.String(),
 	})
 	if err != nil {
-		return parseServerErr(operation, err)
+		return entities.Entry{}, parseServerErr(operation, err)
 	}
-	return nil
+	return parseEntry(accID, resp), nil
 }
 
 // ReserveCreditLimit requests a credit limit reserval to the accounts server
-func (c FakeClient) ReserveCreditLimit(ctx context.Context, accID vos.AccountID, amount vos.Money) error {
+func (c FakeClient) ReserveCreditLimit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error) {
 	const operation = "accounts.Client.ReserveCreditLimit"
-	_, err := c.client.ReserveCreditLimit(ctx, &accounts.Request{
+	resp, err := c.client.ReserveCreditLimit(ctx, &accounts.Request{
+		AccountID:      accID.String(),
+		Amount:         amount.Int64(),
+		Currency:       amount.Currency().String(),
+		IdempotencyKey: key.String(),
+	})
+	if err != nil {
+		return entities.Entry{}, parseServerErr(operation, err)
+	}
+	return parseEntry(accID, resp), nil
+}
+
+// ReleaseCreditLimit requests a credit limit release to the accounts server
+func (c FakeClient) ReleaseCreditLimit(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Entry, error) {
+	const operation = "accounts.Client.ReleaseCreditLimit"
+	resp, err := c.client.ReleaseCreditLimit(ctx, &accounts.Request{
+		AccountID:      accID.String(),
+		Amount:         amount.Int64(),
+		Currency:       amount.Currency().String(),
+		IdempotencyKey: key.String(),
+	})
+	if err != nil {
+		return entities.Entry{}, parseServerErr(operation, err)
+	}
+	return parseEntry(accID, resp), nil
+}
+
+// Transfer requests a transfer between accounts to the accounts server
+func (c FakeClient) Transfer(ctx context.Context, key vos.IdempotencyKey, from, to vos.AccountID, amount vos.Money) (entities.Entry, error) {
+	const operation = "accounts.Client.Transfer"
+	resp, err := c.client.Transfer(ctx, &accounts.TransferRequest{
+		FromAccountID:  from.String(),
+		ToAccountID:    to.String(),
+		Amount:         amount.Int64(),
+		Currency:       amount.Currency().String(),
+		IdempotencyKey: key.String(),
+	})
+	if err != nil {
+		return entities.Entry{}, parseServerErr(operation, err)
+	}
+	return parseEntry(from, resp), nil
+}
+
+// PlaceHold requests a hold of funds to the accounts server
+func (c FakeClient) PlaceHold(ctx context.Context, key vos.IdempotencyKey, accID vos.AccountID, amount vos.Money) (entities.Hold, error) {
+	const operation = "accounts.Client.PlaceHold"
+	hold, err := c.client.PlaceHold(ctx, &accounts.PlaceHoldRequest{
+		AccountID:      accID.String(),
+		Amount:         amount.Int64(),
+		Currency:       amount.Currency().String(),
+		IdempotencyKey: key.String(),
+	})
+	if err != nil {
+		return entities.Hold{}, parseServerErr(operation, err)
+	}
+	return parseHold(hold), nil
+}
+
+// CaptureHold requests a hold capture to the accounts server, a zero amount capturing it in full
+func (c FakeClient) CaptureHold(ctx context.Context, key vos.IdempotencyKey, holdID vos.HoldID, amount vos.Money) (entities.Entry, error) {
+	const operation = "accounts.Client.CaptureHold"
+	resp, err := c.client.CaptureHold(ctx, &accounts.CaptureHoldRequest{
+		HoldID:         holdID.String(),
+		Amount:         amount.Int64(),
+		Currency:       amount.Currency().String(),
+		IdempotencyKey: key.String(),
+	})
+	if err != nil {
+		return entities.Entry{}, parseServerErr(operation, err)
+	}
+	// the account is the held one, not known by the capture request
+	return parseEntry("", resp), nil
+}
+
+// VoidHold requests a hold void to the accounts server
+func (c FakeClient) VoidHold(ctx context.Context, holdID vos.HoldID) (entities.Hold, error) {
+	const operation = "accounts.Client.VoidHold"
+	hold, err := c.client.VoidHold(ctx, &accounts.VoidHoldRequest{
+		HoldID: holdID.String(),
+	})
+	if err != nil {
+		return entities.Hold{}, parseServerErr(operation, err)
+	}
+	return parseHold(hold), nil
+}
+
+func parseHold(hold *accounts.Hold) entities.Hold {
+	currency := vos.Currency(hold.Currency)
+	return entities.Hold{
+		ID:             vos.HoldID(hold.HoldID),
+		AccountID:      vos.AccountID(hold.AccountID),
+		Amount:         vos.NewMoney(hold.Amount, currency),
+		CapturedAmount: vos.NewMoney(hold.CapturedAmount, currency),
+		EntryID:        vos.TransactionID(hold.EntryID),
+		Status:         entities.HoldStatus(hold.Status),
+		ExpiresAt:      hold.ExpiresAt.AsTime(),
+		CreatedAt:      hold.CreatedAt.AsTime(),
+	}
+}
+
+// WatchAccount opens a stream of the account balance updates
+func (c FakeClient) WatchAccount(ctx context.Context, accID vos.AccountID) (*BalanceWatcher, error) {
+	const operation = "accounts.Client.WatchAccount"
+	stream, err := c.client.WatchAccount(ctx, &accounts.WatchAccountRequest{
 		AccountID: accID.String(),
-		Amount:    amount.Int64(),
 	})
 	if err != nil {
-		return parseServerErr(operation, err)
+		return nil, parseServerErr(operation, err)
 	}
-	return nil
+	return &BalanceWatcher{stream: stream}, nil
+}
+
+// BalanceWatcher receives balance updates of a watched account
+type BalanceWatcher struct {
+	stream accounts.AccountsService_WatchAccountClient
+}
+
+// Next blocks until the next balance update, the first one carries the current balance
+func (w BalanceWatcher) Next() (entities.BalanceUpdate, error) {
+	const operation = "accounts.Client.WatchAccount"
+	update, err := w.stream.Recv()
+	if err != nil {
+		return entities.BalanceUpdate{}, parseServerErr(operation, err)
+	}
+	return entities.BalanceUpdate{
+		AccountID:       vos.AccountID(update.AccountID),
+		EntryID:         vos.TransactionID(update.EntryID),
+		Operation:       entities.Operation(update.Operation),
+		HoldID:          vos.HoldID(update.HoldID),
+		HoldStatus:      entities.HoldStatus(update.HoldStatus),
+		Balance:         vos.NewMoney(update.Balance, vos.Currency(update.Currency)),
+		AvailableCredit: vos.NewMoney(update.AvailableCredit, vos.Currency(update.Currency)),
+		Held:            vos.NewMoney(update.Held, vos.Currency(update.Currency)),
+	}, nil
+}
+
+// parseEntry maps a movement response into the entry it produced
+func parseEntry(accID vos.AccountID, resp *accounts.Response) entities.Entry {
+	return entities.Entry{
+		ID:              vos.TransactionID(resp.TransactionID),
+		AccountID:       accID,
+		Balance:         vos.NewMoney(resp.Balance, vos.Currency(resp.Currency)),
+		AvailableCredit: vos.NewMoney(resp.AvailableCredit, vos.Currency(resp.Currency)),
+	}
+}
+
+// serverErrs maps the reasons sent on error details back into domain errors
+var serverErrs = map[string]error{
+	acc_grpc.ReasonAccountNotFound:      usecase.ErrAccountNotFound,
+	acc_grpc.ReasonAccountConflict:      usecase.ErrAccountConflict,
+	acc_grpc.ReasonAccountNotActive:     usecase.ErrAccountNotActive,
+	acc_grpc.ReasonInvalidAccID:         usecase.ErrInvalidAccID,
+	acc_grpc.ReasonInvalidDocument:      usecase.ErrInvalidDocument,
+	acc_grpc.ReasonInvalidCreditLimit:   usecase.ErrInvalidCreditLimit,
+	acc_grpc.ReasonInvalidAmount:        usecase.ErrInvalidAmount,
+	acc_grpc.ReasonAmountOutOfRange:     usecase.ErrAmountOutOfRange,
+	acc_grpc.ReasonInsufficientBalance:  usecase.ErrInsufficientBalance,
+	acc_grpc.ReasonInsufficientCredit:   usecase.ErrInsufficientCredit,
+	acc_grpc.ReasonCreditLimitExceeded:  usecase.ErrCreditLimitExceeded,
+	acc_grpc.ReasonSameAccountTransfer:  usecase.ErrSameAccountTransfer,
+	acc_grpc.ReasonInvalidCurrency:      usecase.ErrInvalidCurrency,
+	acc_grpc.ReasonCurrencyMismatch:     usecase.ErrCurrencyMismatch,
+	acc_grpc.ReasonIdempotencyKeyReused: usecase.ErrIdempotencyKeyReused,
+	acc_grpc.ReasonHoldNotFound:         usecase.ErrHoldNotFound,
+	acc_grpc.ReasonHoldNotActive:        usecase.ErrHoldNotActive,
+	acc_grpc.ReasonCaptureExceedsHold:   usecase.ErrCaptureExceedsHold,
 }
 
 func parseServerErr(operation string, err error) error {
@@ -70,18 +300,14 @@ func parseServerErr(operation string, err error) error {
 	if !ok {
 		return domain.Error(operation, err)
 	}
-	//nolint
-	switch st.Code() {
-	case codes.NotFound:
-		return usecase.ErrAccountNotFound
-	case codes.InvalidArgument:
-		switch st.Message() {
-		case "err::insufficient_balance":
-			return usecase.ErrInsufficientBalance
-		case "err::insufficient_credit":
-			return usecase.ErrInsufficientCredit
-		case "err::invalid_amount":
-			return usecase.ErrInvalidAmount
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != acc_grpc.ErrorDomain {
+			continue
+		}
+		if domainErr, ok := serverErrs[info.Reason]; ok {
+			return domainErr
 		}
 	}
 
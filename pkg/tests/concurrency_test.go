@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Concurrency(t *testing.T) {
+	ctx := context.Background()
+	const requests = 100
+	testTable := []struct {
+		Name                    string
+		Setup                   func(t *testing.T) vos.AccountID
+		Request                 func(accID vos.AccountID) error
+		ExpectedError           error
+		ExpectedBalance         vos.Money
+		ExpectedAvailableCredit vos.Money
+	}{
+		{
+			Name: "concurrent withdrawals never overdraw",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+				require.NoError(t, err)
+
+				_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(requests/2))
+				require.NoError(t, err)
+
+				return accID
+			},
+			Request: func(accID vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Withdrawal(ctx, "", accID, brl(1))
+				return err
+			},
+			ExpectedError:   accounts.ErrInsufficientBalance,
+			ExpectedBalance: brl(0),
+		},
+		{
+			Name: "concurrent credit reservations never exceed the limit",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(requests/2))
+				require.NoError(t, err)
+
+				return accID
+			},
+			Request: func(accID vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.ReserveCreditLimit(ctx, "", accID, brl(1))
+				return err
+			},
+			ExpectedError:           accounts.ErrInsufficientCredit,
+			ExpectedAvailableCredit: brl(0),
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			accID := tt.Setup(t)
+
+			// test
+			errs := make(chan error, requests)
+			var wg sync.WaitGroup
+			for i := 0; i < requests; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- tt.Request(accID)
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			// assert
+			var succeeded, rejected int
+			for err := range errs {
+				if err == nil {
+					succeeded++
+					continue
+				}
+				assert.ErrorIs(t, err, tt.ExpectedError)
+				rejected++
+			}
+			assert.Equal(t, requests/2, succeeded)
+			assert.Equal(t, requests/2, rejected)
+
+			acc, err := testEnv.App.Accounts.GetAccountByID(ctx, accID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedBalance, acc.MainBalance().Balance)
+			assert.Equal(t, tt.ExpectedAvailableCredit, acc.MainBalance().AvailableCredit)
+		})
+	}
+}
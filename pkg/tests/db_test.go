@@ -5,9 +5,11 @@ import (
 	"testing"
 
 	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_CreateAccount_DB(t *testing.T) {
@@ -20,9 +22,9 @@ func Test_CreateAccount_DB(t *testing.T) {
 	}{
 		{
 			Name:            "create acc happy path",
-			Document:        "123",
-			Balance:         0,
-			AvailableCredit: 5000,
+			Document:        "12345678909",
+			Balance:         brl(0),
+			AvailableCredit: brl(5000),
 		},
 	}
 
@@ -46,3 +48,63 @@ func Test_CreateAccount_DB(t *testing.T) {
 		})
 	}
 }
+
+func Test_Ledger_DB(t *testing.T) {
+	ctx := context.Background()
+	defer truncatePostgresTables()
+
+	accID, err := testEnv.AccRepo.CreateAccount(ctx, entities.NewAccount("12345678909", brl(0), brl(100)))
+	require.NoError(t, err)
+
+	// test
+	deposit, err := testEnv.AccRepo.Deposit(ctx, "", accID, brl(50))
+	require.NoError(t, err)
+	withdrawal, err := testEnv.AccRepo.Withdraw(ctx, "", accID, brl(20))
+	require.NoError(t, err)
+	reservation, err := testEnv.AccRepo.DecreaseAvailableCredit(ctx, "", accID, brl(30))
+	require.NoError(t, err)
+
+	// assert
+	for _, entry := range []entities.Entry{deposit, withdrawal, reservation} {
+		_, err = uuid.Parse(entry.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, accID, entry.AccountID)
+	}
+
+	assert.Equal(t, entities.OperationDeposit, deposit.Operation)
+	assert.Equal(t, brl(50), deposit.Balance)
+	assert.Equal(t, entities.OperationWithdrawal, withdrawal.Operation)
+	assert.Equal(t, brl(30), withdrawal.Balance)
+	assert.Equal(t, entities.OperationCreditReservation, reservation.Operation)
+	assert.Equal(t, brl(70), reservation.AvailableCredit)
+
+	var count int
+	err = testEnv.Conn.QueryRow(ctx, "SELECT count(*) FROM entries WHERE account_id = $1", accID.String()).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// entries are append-only
+	_, err = testEnv.Conn.Exec(ctx, "UPDATE entries SET amount = 1 WHERE id = $1", deposit.ID.String())
+	assert.Error(t, err)
+	_, err = testEnv.Conn.Exec(ctx, "DELETE FROM entries WHERE id = $1", deposit.ID.String())
+	assert.Error(t, err)
+}
+
+func Test_Ledger_Rollback_DB(t *testing.T) {
+	ctx := context.Background()
+	defer truncatePostgresTables()
+
+	accID, err := testEnv.AccRepo.CreateAccount(ctx, entities.NewAccount("12345678909", brl(0), brl(0)))
+	require.NoError(t, err)
+
+	// test
+	_, err = testEnv.AccRepo.Withdraw(ctx, "", accID, brl(10))
+
+	// assert
+	assert.ErrorIs(t, err, accounts.ErrInsufficientBalance)
+
+	var count int
+	err = testEnv.Conn.QueryRow(ctx, "SELECT count(*) FROM entries WHERE account_id = $1", accID.String()).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
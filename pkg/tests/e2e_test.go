@@ -5,14 +5,20 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
+	accounts_uc "github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/api/shared"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func Test_CreateAccount(t *testing.T) {
@@ -32,30 +38,94 @@ func Test_CreateAccount(t *testing.T) {
 			Req:                accounts.CreateAccountRequest{Document: "invalid"},
 			ExpectedStatusCode: http.StatusUnprocessableEntity,
 		},
+		{
+			Name:               "unprocessable entity: negative document",
+			Req:                accounts.CreateAccountRequest{Document: "-12345678909"},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:               "unprocessable entity: document too long",
+			Req:                accounts.CreateAccountRequest{Document: "123456789012345678901234567890"},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:               "unprocessable entity: wrong CPF check digits",
+			Req:                accounts.CreateAccountRequest{Document: "123.456.789-00"},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:               "unprocessable entity: repeated CPF digits",
+			Req:                accounts.CreateAccountRequest{Document: "111.111.111-11"},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:               "unprocessable entity: wrong CNPJ check digits",
+			Req:                accounts.CreateAccountRequest{Document: "11.222.333/0001-82"},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			Name:               "unprocessable entity: invalid credit limit",
-			Req:                accounts.CreateAccountRequest{Document: "123", CreditLimit: -1},
+			Req:                accounts.CreateAccountRequest{Document: "12345678909", CreditLimit: brl(-1)},
 			ExpectedStatusCode: http.StatusUnprocessableEntity,
 		},
 		{
 			Name: "conflict: account alread registered",
-			Req:  accounts.CreateAccountRequest{Document: "999", CreditLimit: 10},
+			Req:  accounts.CreateAccountRequest{Document: "98765432100", CreditLimit: brl(10)},
 			Setup: func(t *testing.T) {
 				// create account to simulate duplication
-				_, err := testEnv.App.Accounts.CreateAccount(context.Background(), "999", 0)
+				_, err := testEnv.App.Accounts.CreateAccount(context.Background(), "98765432100", brl(0))
 				require.NoError(t, err)
 
 			},
 			ExpectedStatusCode: http.StatusConflict,
 		},
+		{
+			Name: "conflict: same document with punctuation",
+			Req:  accounts.CreateAccountRequest{Document: "123.456.789-09"},
+			Setup: func(t *testing.T) {
+				_, err := testEnv.App.Accounts.CreateAccount(context.Background(), "12345678909", brl(0))
+				require.NoError(t, err)
+			},
+			ExpectedStatusCode: http.StatusConflict,
+		},
+		{
+			Name:               "create account with punctuated CPF",
+			Req:                accounts.CreateAccountRequest{Document: "123.456.789-09"},
+			ExpectedStatusCode: http.StatusCreated,
+		},
+		{
+			Name:               "create account with CNPJ",
+			Req:                accounts.CreateAccountRequest{Document: "11.222.333/0001-81"},
+			ExpectedStatusCode: http.StatusCreated,
+		},
+		{
+			Name:               "create account with alphanumeric CNPJ",
+			Req:                accounts.CreateAccountRequest{Document: "12.ABC.345/01DE-35"},
+			ExpectedStatusCode: http.StatusCreated,
+		},
 		{
 			Name:               "create account happy path",
-			Req:                accounts.CreateAccountRequest{Document: "123", CreditLimit: 5000},
+			Req:                accounts.CreateAccountRequest{Document: "12345678909", CreditLimit: brl(5000)},
 			ExpectedStatusCode: http.StatusCreated,
 		},
 		{
 			Name:               "create account happy path with default credit limit (zero)",
-			Req:                accounts.CreateAccountRequest{Document: "123"},
+			Req:                accounts.CreateAccountRequest{Document: "12345678909"},
+			ExpectedStatusCode: http.StatusCreated,
+		},
+		{
+			Name:               "unprocessable entity: unknown currency",
+			Req:                accounts.CreateAccountRequest{Document: "12345678909", Currency: "XYZ"},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:               "unprocessable entity: unknown secondary currency",
+			Req:                accounts.CreateAccountRequest{Document: "12345678909", Currencies: []vos.Currency{"XYZ"}},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:               "create account happy path in several currencies",
+			Req:                accounts.CreateAccountRequest{Document: "12345678909", Currency: "usd", Currencies: []vos.Currency{"BRL", "EUR"}},
 			ExpectedStatusCode: http.StatusCreated,
 		},
 	}
@@ -76,7 +146,7 @@ func Test_CreateAccount(t *testing.T) {
 			require.NoError(t, err)
 
 			// test
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := testEnv.HTTPClient.Do(req)
 			require.NoError(t, err)
 			defer resp.Body.Close()
 
@@ -95,6 +165,122 @@ func Test_CreateAccount(t *testing.T) {
 	}
 }
 
+func Test_GetAccount_Currencies(t *testing.T) {
+	defer truncatePostgresTables()
+
+	// prepare
+	body, err := json.Marshal(accounts.CreateAccountRequest{
+		Document:    "12345678909",
+		CreditLimit: vos.NewMoney(5000, vos.CurrencyUSD),
+		Currency:    vos.CurrencyUSD,
+		Currencies:  []vos.Currency{vos.CurrencyEUR, vos.CurrencyBRL, vos.CurrencyUSD},
+	})
+	require.NoError(t, err)
+
+	resp, err := testEnv.HTTPClient.Post(testEnv.Server.URL+"/api/v1/accounts", "application/json", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created accounts.CreateAccountResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+
+	// test
+	resp, err = testEnv.HTTPClient.Get(testEnv.Server.URL + "/api/v1/accounts/" + created.AccountID.String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// assert
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var acc accounts.GetAccountResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&acc))
+	assert.Equal(t, vos.CurrencyUSD, acc.Currency)
+	assert.Equal(t, int64(5000), acc.CreditLimit.Int64())
+
+	currencies := make([]vos.Currency, 0, len(acc.Balances))
+	for _, balance := range acc.Balances {
+		currencies = append(currencies, balance.Currency)
+	}
+	// balances come sorted by currency, each currency once
+	assert.Equal(t, []vos.Currency{vos.CurrencyBRL, vos.CurrencyEUR, vos.CurrencyUSD}, currencies)
+	assert.Equal(t, int64(0), acc.Balances[0].CreditLimit.Int64())
+	assert.Equal(t, int64(5000), acc.Balances[2].AvailableCredit.Int64())
+}
+
+func Test_CreateAccount_DecimalAmounts(t *testing.T) {
+	testTable := []struct {
+		Name                string
+		Body                string
+		ExpectedStatusCode  int
+		ExpectedCreditLimit int64
+	}{
+		{
+			Name:                "integer in minor units",
+			Body:                `{"document_number": "12345678909", "credit_limit": 15025}`,
+			ExpectedStatusCode:  http.StatusCreated,
+			ExpectedCreditLimit: 15025,
+		},
+		{
+			Name:                "decimal in BRL by default",
+			Body:                `{"document_number": "12345678909", "credit_limit": "150.25"}`,
+			ExpectedStatusCode:  http.StatusCreated,
+			ExpectedCreditLimit: 15025,
+		},
+		{
+			Name:                "decimal in a currency without minor units",
+			Body:                `{"document_number": "12345678909", "credit_limit": "150", "currency": "jpy"}`,
+			ExpectedStatusCode:  http.StatusCreated,
+			ExpectedCreditLimit: 150,
+		},
+		{
+			Name:                "decimal in a currency with 3 minor units",
+			Body:                `{"document_number": "12345678909", "credit_limit": "1.5", "currency": "KWD"}`,
+			ExpectedStatusCode:  http.StatusCreated,
+			ExpectedCreditLimit: 1500,
+		},
+		{
+			Name:               "bad request: more decimals than the currency has",
+			Body:               `{"document_number": "12345678909", "credit_limit": "150.5", "currency": "JPY"}`,
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: float number",
+			Body:               `{"document_number": "12345678909", "credit_limit": 150.25}`,
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: decimal out of range",
+			Body:               `{"document_number": "12345678909", "credit_limit": "92233720368547758.08"}`,
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// test
+			resp, err := testEnv.HTTPClient.Post(testEnv.Server.URL+"/api/v1/accounts", "application/json", bytes.NewBufferString(tt.Body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// assert
+			require.Equal(t, tt.ExpectedStatusCode, resp.StatusCode)
+			if resp.StatusCode != http.StatusCreated {
+				return
+			}
+
+			var created accounts.CreateAccountResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+
+			acc, err := testEnv.App.Accounts.GetAccountByID(context.Background(), created.AccountID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedCreditLimit, acc.MainBalance().CreditLimit.Int64())
+		})
+	}
+}
+
 func Test_GetAccount(t *testing.T) {
 	testTable := []struct {
 		Name               string
@@ -117,7 +303,7 @@ func Test_GetAccount(t *testing.T) {
 			AccountID: "55c217e7-177b-4289-afe3-d763c2ded6d9",
 			Setup: func(t *testing.T) vos.AccountID {
 				// creating account
-				accID, err := testEnv.App.Accounts.CreateAccount(context.Background(), "999", 0)
+				accID, err := testEnv.App.Accounts.CreateAccount(context.Background(), "98765432100", brl(0))
 				require.NoError(t, err)
 				return accID
 			},
@@ -140,12 +326,807 @@ func Test_GetAccount(t *testing.T) {
 			require.NoError(t, err)
 
 			// test
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := testEnv.HTTPClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// assert
+			require.Equal(t, tt.ExpectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func Test_GetAccountByDocument(t *testing.T) {
+	testTable := []struct {
+		Name               string
+		Document           string
+		ExpectedStatusCode int
+		ExpectedAccount    bool
+	}{
+		{
+			Name:               "bad request: missing document",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "unprocessable entity: invalid document",
+			Document:           "123",
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:               "404: account not found",
+			Document:           "11144477735",
+			ExpectedStatusCode: http.StatusNotFound,
+		},
+		{
+			Name:               "unformatted document happy path",
+			Document:           "98765432100",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedAccount:    true,
+		},
+		{
+			Name:               "formatted document happy path",
+			Document:           "987.654.321-00",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedAccount:    true,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			accID, err := testEnv.App.Accounts.CreateAccount(context.Background(), "98765432100", brl(0))
+			require.NoError(t, err)
+
+			target := testEnv.Server.URL + "/api/v1/accounts?document_number=" + url.QueryEscape(tt.Document)
+
+			req, err := http.NewRequest(http.MethodGet, target, nil)
+			require.NoError(t, err)
+
+			// test
+			resp, err := testEnv.HTTPClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// assert
+			require.Equal(t, tt.ExpectedStatusCode, resp.StatusCode)
+
+			if !tt.ExpectedAccount {
+				return
+			}
+
+			var body accounts.GetAccountResponse
+			err = json.NewDecoder(resp.Body).Decode(&body)
+			require.NoError(t, err)
+			assert.Equal(t, accID, body.ID)
+			assert.Equal(t, vos.Document("98765432100"), body.Document)
+		})
+	}
+}
+
+func Test_GetAccountStatement(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name               string
+		AccountID          vos.AccountID
+		Query              string
+		Setup              func(t *testing.T) vos.AccountID
+		ExpectedStatusCode int
+		ExpectedBalances   []int64
+		ExpectedNextCursor bool
+	}{
+		{
+			Name:               "bad request: invalid acc id",
+			AccountID:          "123", //invalid uuid
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: invalid cursor",
+			AccountID:          "55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Query:              "cursor=invalid",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: limit too big",
+			AccountID:          "55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Query:              "limit=1000",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: invalid date range",
+			AccountID:          "55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Query:              "from=2021-08-01T00:00:00Z&to=2021-07-01T00:00:00Z",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "404: account not found",
+			AccountID:          "55c217e7-177b-4289-afe3-d763c2ded6d9",
+			ExpectedStatusCode: http.StatusNotFound,
+		},
+		{
+			Name:  "first page newest first with running balance",
+			Query: "limit=2",
+			Setup: func(t *testing.T) vos.AccountID {
+				return setupStatementAccount(ctx, t)
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBalances:   []int64{25, 30},
+			ExpectedNextCursor: true,
+		},
+		{
+			Name: "whole statement",
+			Setup: func(t *testing.T) vos.AccountID {
+				return setupStatementAccount(ctx, t)
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBalances:   []int64{25, 30, 10},
+		},
+		{
+			Name:  "nothing within the date range",
+			Query: "to=2021-01-01T00:00:00Z",
+			Setup: func(t *testing.T) vos.AccountID {
+				return setupStatementAccount(ctx, t)
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedBalances:   []int64{},
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			if tt.Setup != nil {
+				tt.AccountID = tt.Setup(t)
+			}
+
+			// test
+			resp, body := getStatement(t, tt.AccountID, tt.Query)
+
+			// assert
+			require.Equal(t, tt.ExpectedStatusCode, resp.StatusCode)
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			balances := make([]int64, 0, len(body.Entries))
+			for _, entry := range body.Entries {
+				assert.Equal(t, vos.CurrencyBRL, entry.Currency)
+				balances = append(balances, entry.Balance.Int64())
+			}
+			assert.Equal(t, tt.ExpectedBalances, balances)
+			assert.Equal(t, tt.ExpectedNextCursor, body.NextCursor != "")
+		})
+	}
+}
+
+func Test_GetAccountStatement_Pagination(t *testing.T) {
+	defer truncatePostgresTables()
+
+	accID := setupStatementAccount(context.Background(), t)
+
+	// test
+	resp, firstPage := getStatement(t, accID, "limit=2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, secondPage := getStatement(t, accID, "limit=2&cursor="+firstPage.NextCursor)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// assert
+	require.Len(t, firstPage.Entries, 2)
+	require.Len(t, secondPage.Entries, 1)
+	assert.Empty(t, secondPage.NextCursor)
+	assert.Equal(t, "deposit", secondPage.Entries[0].Operation)
+	assert.Equal(t, int64(10), secondPage.Entries[0].Balance.Int64())
+}
+
+// setupStatementAccount creates an account with a deposit of 10, a deposit of 20 and a withdrawal of 5
+func setupStatementAccount(ctx context.Context, t *testing.T) vos.AccountID {
+	accID, err := testEnv.App.Accounts.CreateAccount(ctx, "98765432100", brl(0))
+	require.NoError(t, err)
+
+	_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(10))
+	require.NoError(t, err)
+	_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(20))
+	require.NoError(t, err)
+	_, err = testEnv.App.Accounts.Withdraw(ctx, "", accID, brl(5))
+	require.NoError(t, err)
+
+	return accID
+}
+
+func getStatement(t *testing.T, accID vos.AccountID, query string) (*http.Response, accounts.GetStatementResponse) {
+	target := fmt.Sprintf("%s/api/v1/accounts/%s/statement?%s", testEnv.Server.URL, accID, query)
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	require.NoError(t, err)
+
+	resp, err := testEnv.HTTPClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body accounts.GetStatementResponse
+	if resp.StatusCode == http.StatusOK {
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		require.NoError(t, err)
+	}
+
+	return resp, body
+}
+
+func Test_ListAccounts(t *testing.T) {
+	testTable := []struct {
+		Name               string
+		Query              string
+		ExpectedStatusCode int
+		ExpectedDocuments  []vos.Document
+	}{
+		{
+			Name:               "newest first by default",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{"52998224725", "11144477735", "98765432100"},
+		},
+		{
+			Name:               "oldest first",
+			Query:              "sort=created_at",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{"98765432100", "11144477735", "52998224725"},
+		},
+		{
+			Name:               "richest first",
+			Query:              "sort=-balance",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{"98765432100", "11144477735", "52998224725"},
+		},
+		{
+			Name:               "poorest first",
+			Query:              "sort=balance",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{"52998224725", "11144477735", "98765432100"},
+		},
+		{
+			Name:               "filtered by status",
+			Query:              "status=blocked",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{"11144477735"},
+		},
+		{
+			Name:               "filtered by balance range",
+			Query:              "min_balance=1&max_balance=50",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{"11144477735"},
+		},
+		{
+			Name:               "filtered by decimal balance range",
+			Query:              "min_balance=0.01&max_balance=0.50",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{"11144477735"},
+		},
+		{
+			Name:               "bad request: too many decimal places",
+			Query:              "min_balance=0.001",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "filtered by formatted document prefix",
+			Query:              "document_prefix=" + url.QueryEscape("987.654"),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{"98765432100"},
+		},
+		{
+			Name:               "filtered by creation date",
+			Query:              "created_to=2000-01-01T00:00:00Z",
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedDocuments:  []vos.Document{},
+		},
+		{
+			Name:               "bad request: unknown sort",
+			Query:              "sort=document",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: unknown status",
+			Query:              "status=frozen",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: inverted balance range",
+			Query:              "min_balance=10&max_balance=5",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: invalid cursor",
+			Query:              "cursor=abc",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			setupListedAccounts(context.Background(), t)
+
+			// test
+			resp, body := listAccounts(t, tt.Query)
+
+			// assert
+			require.Equal(t, tt.ExpectedStatusCode, resp.StatusCode)
+
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			docs := make([]vos.Document, 0, len(body.Accounts))
+			for _, acc := range body.Accounts {
+				docs = append(docs, acc.Document)
+			}
+			assert.Equal(t, tt.ExpectedDocuments, docs)
+			assert.Empty(t, body.NextCursor)
+		})
+	}
+}
+
+func Test_ListAccounts_Pagination(t *testing.T) {
+	defer truncatePostgresTables()
+
+	setupListedAccounts(context.Background(), t)
+
+	// test
+	resp, firstPage := listAccounts(t, "sort=-balance&limit=2")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, secondPage := listAccounts(t, "sort=-balance&limit=2&cursor="+firstPage.NextCursor)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// assert
+	require.Len(t, firstPage.Accounts, 2)
+	assert.Equal(t, vos.Document("98765432100"), firstPage.Accounts[0].Document)
+	assert.Equal(t, vos.Document("11144477735"), firstPage.Accounts[1].Document)
+	require.NotEmpty(t, firstPage.NextCursor)
+
+	require.Len(t, secondPage.Accounts, 1)
+	assert.Equal(t, vos.Document("52998224725"), secondPage.Accounts[0].Document)
+	assert.Empty(t, secondPage.NextCursor)
+}
+
+// setupListedAccounts creates, in this order, an active account with balance 100,
+// a blocked one with balance 50 and an active one with no balance
+func Test_ListAccounts_BalanceAcrossCurrencies(t *testing.T) {
+	defer truncatePostgresTables()
+	ctx := context.Background()
+	usd := func(amount int64) vos.Money { return vos.NewMoney(amount, vos.CurrencyUSD) }
+
+	// prepare
+	for doc, balance := range map[vos.Document]vos.Money{
+		"98765432100": brl(100),
+		"11144477735": usd(500),
+		"52998224725": usd(10),
+	} {
+		accID, err := testEnv.App.Accounts.CreateAccount(ctx, doc, vos.NewMoney(0, balance.Currency()))
+		require.NoError(t, err)
+		_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, balance)
+		require.NoError(t, err)
+	}
+
+	// test: one account per page so that every cursor crosses the sorting
+	var docs []vos.Document
+	cursor := ""
+	for {
+		resp, page := listAccounts(t, "sort=balance&limit=1&cursor="+cursor)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		for _, acc := range page.Accounts {
+			docs = append(docs, acc.Document)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	// assert: balances are never compared across currencies
+	assert.Equal(t, []vos.Document{"98765432100", "52998224725", "11144477735"}, docs)
+}
+
+func setupListedAccounts(ctx context.Context, t *testing.T) {
+	rich, err := testEnv.App.Accounts.CreateAccount(ctx, "98765432100", brl(0))
+	require.NoError(t, err)
+	_, err = testEnv.App.Accounts.Deposit(ctx, "", rich, brl(100))
+	require.NoError(t, err)
+
+	blocked, err := testEnv.App.Accounts.CreateAccount(ctx, "11144477735", brl(0))
+	require.NoError(t, err)
+	_, err = testEnv.App.Accounts.Deposit(ctx, "", blocked, brl(50))
+	require.NoError(t, err)
+	_, err = testEnv.App.Accounts.BlockAccount(ctx, blocked)
+	require.NoError(t, err)
+
+	_, err = testEnv.App.Accounts.CreateAccount(ctx, "52998224725", brl(0))
+	require.NoError(t, err)
+}
+
+func listAccounts(t *testing.T, query string) (*http.Response, accounts.ListAccountsResponse) {
+	target := fmt.Sprintf("%s/admin/v1/accounts?%s", testEnv.Server.URL, query)
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	require.NoError(t, err)
+
+	resp, err := testEnv.HTTPClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body accounts.ListAccountsResponse
+	if resp.StatusCode == http.StatusOK {
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		require.NoError(t, err)
+	}
+
+	return resp, body
+}
+
+func Test_UpdateAccountStatus(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name               string
+		AccountID          vos.AccountID
+		Action             string
+		Setup              func(t *testing.T) vos.AccountID
+		ExpectedStatusCode int
+		ExpectedStatus     string
+	}{
+		{
+			Name:               "bad request: invalid acc id",
+			AccountID:          "123", //invalid uuid
+			Action:             "block",
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "404: account not found",
+			AccountID:          "55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Action:             "block",
+			ExpectedStatusCode: http.StatusNotFound,
+		},
+		{
+			Name:   "block active account",
+			Action: "block",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "98765432100", brl(0))
+				require.NoError(t, err)
+				return accID
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     "blocked",
+		},
+		{
+			Name:   "conflict: unblock active account",
+			Action: "unblock",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "98765432100", brl(0))
+				require.NoError(t, err)
+				return accID
+			},
+			ExpectedStatusCode: http.StatusConflict,
+		},
+		{
+			Name:   "unblock blocked account",
+			Action: "unblock",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "98765432100", brl(0))
+				require.NoError(t, err)
+				_, err = testEnv.App.Accounts.BlockAccount(ctx, accID)
+				require.NoError(t, err)
+				return accID
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     "active",
+		},
+		{
+			Name:   "close blocked account",
+			Action: "close",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "98765432100", brl(0))
+				require.NoError(t, err)
+				_, err = testEnv.App.Accounts.BlockAccount(ctx, accID)
+				require.NoError(t, err)
+				return accID
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedStatus:     "closed",
+		},
+		{
+			Name:   "conflict: unblock closed account",
+			Action: "unblock",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "98765432100", brl(0))
+				require.NoError(t, err)
+				_, err = testEnv.App.Accounts.CloseAccount(ctx, accID)
+				require.NoError(t, err)
+				return accID
+			},
+			ExpectedStatusCode: http.StatusConflict,
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			if tt.Setup != nil {
+				tt.AccountID = tt.Setup(t)
+			}
+
+			target := fmt.Sprintf("%s/admin/v1/accounts/%s/%s", testEnv.Server.URL, tt.AccountID, tt.Action)
+
+			req, err := http.NewRequest(http.MethodPost, target, nil)
+			require.NoError(t, err)
+
+			// test
+			resp, err := testEnv.HTTPClient.Do(req)
 			require.NoError(t, err)
 			defer resp.Body.Close()
 
 			// assert
 			require.Equal(t, tt.ExpectedStatusCode, resp.StatusCode)
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			var respBody accounts.GetAccountResponse
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedStatus, respBody.Status)
 		})
 	}
 }
+
+func Test_UpdateCreditLimit(t *testing.T) {
+	ctx := context.Background()
+	limit := func(m vos.Money) *vos.Money { return &m }
+	testTable := []struct {
+		Name                    string
+		AccountID               vos.AccountID
+		Req                     accounts.UpdateCreditLimitRequest
+		ExpectedStatusCode      int
+		ExpectedCreditLimit     vos.Money
+		ExpectedAvailableCredit vos.Money
+	}{
+		{
+			Name:               "bad request: invalid acc id",
+			AccountID:          "123", //invalid uuid
+			Req:                accounts.UpdateCreditLimitRequest{CreditLimit: limit(brl(200)), Reason: "income increased"},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: missing credit limit",
+			Req:                accounts.UpdateCreditLimitRequest{Reason: "income increased"},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "bad request: missing reason",
+			Req:                accounts.UpdateCreditLimitRequest{CreditLimit: limit(brl(200))},
+			ExpectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:               "404: account not found",
+			AccountID:          "55c217e7-177b-4289-afe3-d763c2ded6d9",
+			Req:                accounts.UpdateCreditLimitRequest{CreditLimit: limit(brl(200)), Reason: "income increased"},
+			ExpectedStatusCode: http.StatusNotFound,
+		},
+		{
+			Name:               "unprocessable entity: negative credit limit",
+			Req:                accounts.UpdateCreditLimitRequest{CreditLimit: limit(brl(-1)), Reason: "income decreased"},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:               "unprocessable entity: credit limit below reserved credit",
+			Req:                accounts.UpdateCreditLimitRequest{CreditLimit: limit(brl(29)), Reason: "income decreased"},
+			ExpectedStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:                    "raise credit limit",
+			Req:                     accounts.UpdateCreditLimitRequest{CreditLimit: limit(brl(200)), Reason: "income increased"},
+			ExpectedStatusCode:      http.StatusOK,
+			ExpectedCreditLimit:     brl(200),
+			ExpectedAvailableCredit: brl(170),
+		},
+		{
+			Name:                    "lower credit limit",
+			Req:                     accounts.UpdateCreditLimitRequest{CreditLimit: limit(brl(50)), Reason: "income decreased"},
+			ExpectedStatusCode:      http.StatusOK,
+			ExpectedCreditLimit:     brl(50),
+			ExpectedAvailableCredit: brl(20),
+		},
+		{
+			Name:                    "lower credit limit down to reserved credit",
+			Req:                     accounts.UpdateCreditLimitRequest{CreditLimit: limit(brl(30)), Reason: "income decreased"},
+			ExpectedStatusCode:      http.StatusOK,
+			ExpectedCreditLimit:     brl(30),
+			ExpectedAvailableCredit: brl(0),
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			accID, err := testEnv.App.Accounts.CreateAccount(ctx, "98765432100", brl(100))
+			require.NoError(t, err)
+			_, err = testEnv.App.Accounts.ReserveCreditLimit(ctx, "", accID, brl(30))
+			require.NoError(t, err)
+			if tt.AccountID == "" {
+				tt.AccountID = accID
+			}
+
+			target := fmt.Sprintf("%s/admin/v1/accounts/%s/credit-limit", testEnv.Server.URL, tt.AccountID)
+			body, err := json.Marshal(tt.Req)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPut, target, bytes.NewBuffer(body))
+			require.NoError(t, err)
+
+			// test
+			resp, err := testEnv.HTTPClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// assert
+			require.Equal(t, tt.ExpectedStatusCode, resp.StatusCode)
+
+			var changes int
+			err = testEnv.Conn.QueryRow(ctx,
+				`SELECT count(*) FROM credit_limit_changes WHERE account_id = $1 AND changed_by = $2 AND reason = $3`,
+				accID.String(), testTokenSubject, tt.Req.Reason,
+			).Scan(&changes)
+			require.NoError(t, err)
+
+			if resp.StatusCode != http.StatusOK {
+				assert.Zero(t, changes)
+				return
+			}
+			assert.Equal(t, 1, changes)
+
+			var respBody accounts.GetAccountResponse
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedCreditLimit.Int64(), respBody.CreditLimit.Int64())
+			assert.Equal(t, tt.ExpectedAvailableCredit.Int64(), respBody.AvailableCredit.Int64())
+		})
+	}
+}
+
+func Test_RequestID(t *testing.T) {
+	testTable := []struct {
+		Name      string
+		RequestID string
+	}{
+		{
+			Name:      "incoming request id is honored",
+			RequestID: "3f1c8a52-5b8e-4c1f-9d0a-8f3e0b6a2c71",
+		},
+		{
+			Name: "request id is created when missing",
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			// prepare
+			target := testEnv.Server.URL + "/api/v1/accounts/55c217e7-177b-4289-afe3-d763c2ded6d9"
+			req, err := http.NewRequest(http.MethodGet, target, nil)
+			require.NoError(t, err)
+			if tt.RequestID != "" {
+				req.Header.Set(shared.XReqID, tt.RequestID)
+			}
+
+			// test
+			resp, err := testEnv.HTTPClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// assert
+			reqID := resp.Header.Get(shared.XReqID)
+			if tt.RequestID != "" {
+				assert.Equal(t, tt.RequestID, reqID)
+				return
+			}
+			_, err = uuid.Parse(reqID)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_Metrics(t *testing.T) {
+	defer truncatePostgresTables()
+	ctx := context.Background()
+
+	// prepare
+	accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+	require.NoError(t, err)
+
+	_, err = testEnv.GrpcFakeClient.Deposit(ctx, "", accID, brl(150))
+	require.NoError(t, err)
+	_, err = testEnv.GrpcFakeClient.ReserveCreditLimit(ctx, "", accID, brl(30))
+	require.NoError(t, err)
+	_, err = testEnv.GrpcFakeClient.Withdrawal(ctx, "", accID, brl(1000))
+	require.ErrorIs(t, err, accounts_uc.ErrInsufficientBalance)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/accounts/%s", testEnv.Server.URL, accID), nil)
+	require.NoError(t, err)
+	resp, err := testEnv.HTTPClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// test
+	resp, err = http.Get(testEnv.Server.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// assert
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	for _, expected := range []string{
+		`mybank_acc_http_requests_total{method="GET",route="/api/v1/accounts/{account_id}",status="200"}`,
+		`mybank_acc_http_request_duration_seconds_count{method="GET",route="/api/v1/accounts/{account_id}",status="200"}`,
+		`mybank_acc_grpc_requests_total{code="OK",method="/AccountsService/Deposit"}`,
+		`mybank_acc_grpc_requests_total{code="InvalidArgument",method="/AccountsService/Withdrawal"}`,
+		`mybank_acc_grpc_request_duration_seconds_count{code="OK",method="/AccountsService/ReserveCreditLimit"}`,
+		`mybank_acc_accounts_movements_total{currency="BRL",operation="deposit"}`,
+		`mybank_acc_accounts_movements_amount_total{currency="BRL",operation="credit_reservation"}`,
+		`mybank_acc_accounts_usecase_errors_total{error="insufficient_balance",operation="accounts.Usecase.Withdraw"}`,
+	} {
+		assert.Contains(t, string(body), expected)
+	}
+}
+
+func Test_Tracing(t *testing.T) {
+	defer truncatePostgresTables()
+	ctx := context.Background()
+
+	// prepare
+	accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+	require.NoError(t, err)
+	testEnv.Spans.Reset()
+
+	// test
+	_, err = testEnv.GrpcFakeClient.Deposit(ctx, "", accID, brl(150))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/accounts/%s", testEnv.Server.URL, accID), nil)
+	require.NoError(t, err)
+	resp, err := testEnv.HTTPClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// assert
+	spanByName := func(name string) (tracetest.SpanStub, bool) {
+		for _, span := range testEnv.Spans.GetSpans() {
+			if span.Name == name {
+				return span, true
+			}
+		}
+		return tracetest.SpanStub{}, false
+	}
+
+	// the REST span may only be ended after the response was written
+	require.Eventually(t, func() bool {
+		_, found := spanByName("GET /api/v1/accounts/{account_id}")
+		return found
+	}, time.Second, 10*time.Millisecond)
+
+	for _, chain := range [][]string{
+		{"AccountsService/Deposit", "accounts.Usecase.Deposit", "postgres.AccountsRepository.Deposit", "Deposit"},
+		{"AccountsService/Deposit", "accounts.Usecase.Deposit", "postgres.AccountsRepository.Deposit", "CreateEntry"},
+		{"GET /api/v1/accounts/{account_id}", "accounts.Usecase.GetAccountByID", "postgres.AccountsRepository.GetAccountByID", "GetAccountByID"},
+	} {
+		// every span must be a child of the previous one
+		parent, found := spanByName(chain[0])
+		require.True(t, found, chain[0])
+		for _, name := range chain[1:] {
+			span, found := spanByName(name)
+			require.True(t, found, name)
+			assert.Equal(t, parent.SpanContext.TraceID(), span.SpanContext.TraceID(), name)
+			assert.Equal(t, parent.SpanContext.SpanID(), span.Parent.SpanID(), name)
+			parent = span
+		}
+	}
+}
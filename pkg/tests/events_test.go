@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Outbox(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name           string
+		Run            func(t *testing.T, accID, otherID vos.AccountID)
+		ExpectedEvents []entities.EventType
+		ExpectedOther  []entities.EventType
+	}{
+		{
+			Name:           "account creation",
+			Run:            func(t *testing.T, accID, otherID vos.AccountID) {},
+			ExpectedEvents: []entities.EventType{entities.EventAccountCreated},
+			ExpectedOther:  []entities.EventType{entities.EventAccountCreated},
+		},
+		{
+			Name: "movements are published in order",
+			Run: func(t *testing.T, accID, otherID vos.AccountID) {
+				_, err := testEnv.GrpcFakeClient.Deposit(ctx, "", accID, brl(100))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.ReserveCreditLimit(ctx, "", accID, brl(30))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.ReleaseCreditLimit(ctx, "", accID, brl(10))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.Withdrawal(ctx, "", accID, brl(40))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.Transfer(ctx, "", accID, otherID, brl(50))
+				require.NoError(t, err)
+			},
+			ExpectedEvents: []entities.EventType{
+				entities.EventAccountCreated,
+				entities.EventAccountCredited,
+				entities.EventCreditReserved,
+				entities.EventCreditReleased,
+				entities.EventAccountDebited,
+				entities.EventAccountDebited,
+			},
+			ExpectedOther: []entities.EventType{
+				entities.EventAccountCreated,
+				entities.EventAccountCredited,
+			},
+		},
+		{
+			Name: "failed and replayed movements publish nothing",
+			Run: func(t *testing.T, accID, otherID vos.AccountID) {
+				_, err := testEnv.GrpcFakeClient.Deposit(ctx, "key-1", accID, brl(100))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.Deposit(ctx, "key-1", accID, brl(100))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.Withdrawal(ctx, "", accID, brl(1000))
+				require.ErrorIs(t, err, accounts.ErrInsufficientBalance)
+			},
+			ExpectedEvents: []entities.EventType{
+				entities.EventAccountCreated,
+				entities.EventAccountCredited,
+			},
+			ExpectedOther: []entities.EventType{entities.EventAccountCreated},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+			defer testEnv.Events.Reset()
+
+			// prepare
+			accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+			require.NoError(t, err)
+			otherID, err := testEnv.App.Accounts.CreateAccount(ctx, "11144477735", brl(0))
+			require.NoError(t, err)
+
+			// test
+			tt.Run(t, accID, otherID)
+
+			// assert
+			eventTypes := func(accID vos.AccountID) []entities.EventType {
+				var types []entities.EventType
+				for _, event := range testEnv.Events.Events(accID) {
+					types = append(types, event.Type)
+				}
+				return types
+			}
+
+			require.Eventually(t, func() bool {
+				return len(eventTypes(accID)) >= len(tt.ExpectedEvents) &&
+					len(eventTypes(otherID)) >= len(tt.ExpectedOther)
+			}, time.Second, 20*time.Millisecond)
+
+			// late events would show up on the next relay poll
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, tt.ExpectedEvents, eventTypes(accID))
+			assert.Equal(t, tt.ExpectedOther, eventTypes(otherID))
+		})
+	}
+}
+
+func Test_Outbox_ConcurrentMovements(t *testing.T) {
+	defer truncatePostgresTables()
+	defer testEnv.Events.Reset()
+	ctx := context.Background()
+	const movements = 50
+
+	// prepare: balances in different currencies get locked apart from each other
+	accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0), vos.CurrencyUSD)
+	require.NoError(t, err)
+
+	// test
+	var wg sync.WaitGroup
+	for i := 0; i < movements; i++ {
+		currency := vos.CurrencyBRL
+		if i%2 == 1 {
+			currency = vos.CurrencyUSD
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := testEnv.App.Accounts.Deposit(ctx, "", accID, vos.NewMoney(1, currency))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// assert: the relay publishes events as they commit, by increasing outbox id,
+	// so events committed out of the order of their ids get published out of it as well
+	rows, err := testEnv.Conn.Query(ctx, `SELECT event_id::text FROM outbox WHERE account_id = $1 ORDER BY id`, accID.String())
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var expected []string
+	for rows.Next() {
+		var eventID string
+		require.NoError(t, rows.Scan(&eventID))
+		expected = append(expected, eventID)
+	}
+	require.NoError(t, rows.Err())
+	require.Len(t, expected, movements+1)
+
+	require.Eventually(t, func() bool {
+		return len(testEnv.Events.Events(accID)) == len(expected)
+	}, time.Second, 20*time.Millisecond)
+
+	var published []string
+	for _, event := range testEnv.Events.Events(accID) {
+		published = append(published, event.ID)
+	}
+	assert.Equal(t, expected, published)
+}
+
+func Test_Outbox_EventData(t *testing.T) {
+	defer truncatePostgresTables()
+	defer testEnv.Events.Reset()
+	ctx := context.Background()
+
+	// prepare
+	accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+	require.NoError(t, err)
+
+	// test
+	entry, err := testEnv.App.Accounts.Deposit(ctx, "", accID, brl(150))
+	require.NoError(t, err)
+
+	// assert
+	require.Eventually(t, func() bool {
+		return len(testEnv.Events.Events(accID)) == 2
+	}, time.Second, 20*time.Millisecond)
+
+	events := testEnv.Events.Events(accID)
+	assert.NotEqual(t, events[0].ID, events[1].ID)
+
+	var created entities.AccountCreatedData
+	require.NoError(t, json.Unmarshal(events[0].Data.(json.RawMessage), &created))
+	assert.Equal(t, accID, created.AccountID)
+	assert.Equal(t, vos.DocumentTypeCPF, created.DocumentType)
+	assert.Equal(t, vos.CurrencyBRL, created.Currency)
+	assert.Equal(t, []vos.Currency{vos.CurrencyBRL}, created.Currencies)
+	// amounts are encoded in minor units, their currency being carried apart
+	assert.Equal(t, int64(0), created.Balance.Int64())
+	assert.Equal(t, int64(100), created.CreditLimit.Int64())
+
+	var credited entities.EntryData
+	require.NoError(t, json.Unmarshal(events[1].Data.(json.RawMessage), &credited))
+	assert.Equal(t, entry.ID, credited.EntryID)
+	assert.Equal(t, entities.OperationDeposit, credited.Operation)
+	assert.Equal(t, vos.CurrencyBRL, credited.Currency)
+	assert.Equal(t, int64(150), credited.Amount.Int64())
+	assert.Equal(t, int64(150), credited.Balance.Int64())
+}
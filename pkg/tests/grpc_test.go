@@ -2,12 +2,26 @@ package tests
 
 import (
 	"context"
+	"math"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/fernandodr19/mybank-acc/pkg/domain/entities"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/usecases/accounts"
 	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/events"
+	acc_grpc "github.com/fernandodr19/mybank-acc/pkg/gateway/grpc"
+	acc_pb "github.com/fernandodr19/mybank-acc/pkg/gateway/grpc/accounts"
+	"github.com/fernandodr19/mybank-acc/pkg/tests/clients"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func Test_Deposit(t *testing.T) {
@@ -23,22 +37,35 @@ func Test_Deposit(t *testing.T) {
 		{
 			Name:          "expected invalid amount",
 			AccID:         "e031a99d-6191-4d02-8616-b5e3530caccb",
-			Amount:        -10,
+			Amount:        brl(-10),
 			ExpectedError: accounts.ErrInvalidAmount,
 		},
 		{
 			Name:          "expected invalid acc id",
 			AccID:         "24dde2d4-5763-419d-9a93-3365ef55255c",
-			Amount:        10,
+			Amount:        brl(10),
 			ExpectedError: accounts.ErrAccountNotFound,
 		},
 		{
 			Name: "deposit happy path",
 			Setup: func() (vos.AccountID, error) {
-				return testEnv.App.Accounts.CreateAccount(ctx, "123", 0)
+				return testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
 			},
-			Amount:          10,
-			ExpectedBalance: 10,
+			Amount:          brl(10),
+			ExpectedBalance: brl(10),
+		},
+		{
+			Name: "expected amount out of range",
+			Setup: func() (vos.AccountID, error) {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+				if err != nil {
+					return "", err
+				}
+				_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(math.MaxInt64))
+				return accID, err
+			},
+			Amount:        brl(1),
+			ExpectedError: accounts.ErrAmountOutOfRange,
 		},
 	}
 
@@ -54,7 +81,7 @@ func Test_Deposit(t *testing.T) {
 			}
 
 			// test
-			err := testEnv.GrpcFakeClient.Deposit(ctx, tt.AccID, tt.Amount)
+			entry, err := testEnv.GrpcFakeClient.Deposit(ctx, "", tt.AccID, tt.Amount)
 
 			// assert
 			assert.ErrorIs(t, tt.ExpectedError, err)
@@ -63,9 +90,12 @@ func Test_Deposit(t *testing.T) {
 				return
 			}
 
+			assert.NotEmpty(t, entry.ID)
+			assert.Equal(t, tt.ExpectedBalance, entry.Balance)
+
 			acc, err := testEnv.App.Accounts.GetAccountByID(ctx, tt.AccID)
 			require.NoError(t, err)
-			assert.Equal(t, tt.ExpectedBalance, acc.Balance)
+			assert.Equal(t, tt.ExpectedBalance, acc.MainBalance().Balance)
 		})
 	}
 }
@@ -83,53 +113,53 @@ func Test_Withdraw(t *testing.T) {
 		{
 			Name:          "expected invalid amount",
 			AccID:         "e031a99d-6191-4d02-8616-b5e3530caccb",
-			Amount:        -10,
+			Amount:        brl(-10),
 			ExpectedError: accounts.ErrInvalidAmount,
 		},
 		{
 			Name:          "expected invalid acc id",
 			AccID:         "24dde2d4-5763-419d-9a93-3365ef55255c",
-			Amount:        10,
+			Amount:        brl(10),
 			ExpectedError: accounts.ErrAccountNotFound,
 		},
 		{
 			Name: "insufficient balance",
 			Setup: func(t *testing.T) vos.AccountID {
-				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "123", 0)
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
 				require.NoError(t, err)
 
 				return accID
 			},
-			Amount:        10,
+			Amount:        brl(10),
 			ExpectedError: accounts.ErrInsufficientBalance,
 		},
 		{
 			Name: "withdraw happy path",
 			Setup: func(t *testing.T) vos.AccountID {
-				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "123", 0)
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
 				require.NoError(t, err)
 
-				err = testEnv.App.Accounts.Deposit(ctx, accID, 10)
+				_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(10))
 				require.NoError(t, err)
 
 				return accID
 			},
-			Amount:          10,
-			ExpectedBalance: 0,
+			Amount:          brl(10),
+			ExpectedBalance: brl(0),
 		},
 		{
 			Name: "withdraw happy path rich",
 			Setup: func(t *testing.T) vos.AccountID {
-				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "123", 0)
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
 				require.NoError(t, err)
 
-				err = testEnv.App.Accounts.Deposit(ctx, accID, 200000000)
+				_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(200000000))
 				require.NoError(t, err)
 
 				return accID
 			},
-			Amount:          10,
-			ExpectedBalance: 199999990,
+			Amount:          brl(10),
+			ExpectedBalance: brl(199999990),
 		},
 	}
 
@@ -143,7 +173,7 @@ func Test_Withdraw(t *testing.T) {
 			}
 
 			// test
-			err := testEnv.GrpcFakeClient.Withdrawal(ctx, tt.AccID, tt.Amount)
+			_, err := testEnv.GrpcFakeClient.Withdrawal(ctx, "", tt.AccID, tt.Amount)
 
 			// assert
 			assert.ErrorIs(t, tt.ExpectedError, err)
@@ -154,7 +184,7 @@ func Test_Withdraw(t *testing.T) {
 
 			acc, err := testEnv.App.Accounts.GetAccountByID(ctx, tt.AccID)
 			require.NoError(t, err)
-			assert.Equal(t, tt.ExpectedBalance, acc.Balance)
+			assert.Equal(t, tt.ExpectedBalance, acc.MainBalance().Balance)
 		})
 	}
 }
@@ -172,38 +202,38 @@ func Test_Credit(t *testing.T) {
 		{
 			Name:          "expected invalid amount",
 			AccID:         "e031a99d-6191-4d02-8616-b5e3530caccb",
-			Amount:        -10,
+			Amount:        brl(-10),
 			ExpectedError: accounts.ErrInvalidAmount,
 		},
 		{
 			Name:          "expected invalid acc id",
 			AccID:         "24dde2d4-5763-419d-9a93-3365ef55255c",
-			Amount:        10,
+			Amount:        brl(10),
 			ExpectedError: accounts.ErrAccountNotFound,
 		},
 		{
 			Name: "insufficient credit limit",
 			Setup: func() (vos.AccountID, error) {
-				return testEnv.App.Accounts.CreateAccount(ctx, "123", 10)
+				return testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(10))
 			},
-			Amount:                  11,
+			Amount:                  brl(11),
 			ExpectedError:           accounts.ErrInsufficientCredit,
-			ExpectedAvailableCredit: 10,
+			ExpectedAvailableCredit: brl(10),
 		},
 		{
 			Name: "credit happy path",
 			Setup: func() (vos.AccountID, error) {
-				return testEnv.App.Accounts.CreateAccount(ctx, "123", 10)
+				return testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(10))
 			},
-			Amount: 10,
+			Amount: brl(10),
 		},
 		{
 			Name: "credit happy path rich",
 			Setup: func() (vos.AccountID, error) {
-				return testEnv.App.Accounts.CreateAccount(ctx, "123", 999999990)
+				return testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(999999990))
 			},
-			Amount:                  70,
-			ExpectedAvailableCredit: 999999920,
+			Amount:                  brl(70),
+			ExpectedAvailableCredit: brl(999999920),
 		},
 	}
 
@@ -219,7 +249,171 @@ func Test_Credit(t *testing.T) {
 			}
 
 			// test
-			err := testEnv.GrpcFakeClient.ReserveCreditLimit(ctx, tt.AccID, tt.Amount)
+			_, err := testEnv.GrpcFakeClient.ReserveCreditLimit(ctx, "", tt.AccID, tt.Amount)
+
+			// assert
+			assert.ErrorIs(t, tt.ExpectedError, err)
+
+			if err != nil {
+				return
+			}
+
+			acc, err := testEnv.App.Accounts.GetAccountByID(ctx, tt.AccID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedAvailableCredit, acc.MainBalance().AvailableCredit)
+		})
+	}
+}
+
+func Test_Idempotency(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name            string
+		Replay          func(accID vos.AccountID) error
+		ExpectedError   error
+		ExpectedBalance vos.Money
+	}{
+		{
+			Name: "replayed deposit is credited once",
+			Replay: func(accID vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Deposit(ctx, "deposit-key", accID, brl(10))
+				return err
+			},
+			ExpectedBalance: brl(100),
+		},
+		{
+			Name: "replayed withdrawal is debited once",
+			Replay: func(accID vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Withdrawal(ctx, "withdrawal-key", accID, brl(100))
+				if err != nil {
+					return err
+				}
+				_, err = testEnv.GrpcFakeClient.Withdrawal(ctx, "withdrawal-key", accID, brl(100))
+				return err
+			},
+			ExpectedBalance: brl(0),
+		},
+		{
+			Name: "key reused with a different amount",
+			Replay: func(accID vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Deposit(ctx, "deposit-key", accID, brl(20))
+				return err
+			},
+			ExpectedError:   accounts.ErrIdempotencyKeyReused,
+			ExpectedBalance: brl(100),
+		},
+		{
+			Name: "key reused by a different operation",
+			Replay: func(accID vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Withdrawal(ctx, "deposit-key", accID, brl(10))
+				return err
+			},
+			ExpectedError:   accounts.ErrIdempotencyKeyReused,
+			ExpectedBalance: brl(100),
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+			require.NoError(t, err)
+
+			_, err = testEnv.GrpcFakeClient.Deposit(ctx, "deposit-key", accID, brl(10))
+			require.NoError(t, err)
+
+			_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(90))
+			require.NoError(t, err)
+
+			// test
+			err = tt.Replay(accID)
+
+			// assert
+			assert.ErrorIs(t, err, tt.ExpectedError)
+
+			acc, err := testEnv.App.Accounts.GetAccountByID(ctx, accID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedBalance, acc.MainBalance().Balance)
+		})
+	}
+}
+
+func Test_ReleaseCredit(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name                    string
+		AccID                   vos.AccountID
+		Amount                  vos.Money
+		Setup                   func(t *testing.T) vos.AccountID
+		ExpectedError           error
+		ExpectedAvailableCredit vos.Money
+	}{
+		{
+			Name:          "expected invalid amount",
+			AccID:         "e031a99d-6191-4d02-8616-b5e3530caccb",
+			Amount:        brl(-10),
+			ExpectedError: accounts.ErrInvalidAmount,
+		},
+		{
+			Name:          "expected invalid acc id",
+			AccID:         "24dde2d4-5763-419d-9a93-3365ef55255c",
+			Amount:        brl(10),
+			ExpectedError: accounts.ErrAccountNotFound,
+		},
+		{
+			Name: "credit limit exceeded",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(10))
+				require.NoError(t, err)
+
+				return accID
+			},
+			Amount:        brl(1),
+			ExpectedError: accounts.ErrCreditLimitExceeded,
+		},
+		{
+			Name: "release partial credit",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+				require.NoError(t, err)
+
+				_, err = testEnv.App.Accounts.ReserveCreditLimit(ctx, "", accID, brl(40))
+				require.NoError(t, err)
+
+				return accID
+			},
+			Amount:                  brl(30),
+			ExpectedAvailableCredit: brl(90),
+		},
+		{
+			Name: "release whole reserved credit",
+			Setup: func(t *testing.T) vos.AccountID {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+				require.NoError(t, err)
+
+				_, err = testEnv.App.Accounts.ReserveCreditLimit(ctx, "", accID, brl(40))
+				require.NoError(t, err)
+
+				return accID
+			},
+			Amount:                  brl(40),
+			ExpectedAvailableCredit: brl(100),
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			if tt.Setup != nil {
+				tt.AccID = tt.Setup(t)
+			}
+
+			// test
+			_, err := testEnv.GrpcFakeClient.ReleaseCreditLimit(ctx, "", tt.AccID, tt.Amount)
 
 			// assert
 			assert.ErrorIs(t, tt.ExpectedError, err)
@@ -230,7 +424,1083 @@ func Test_Credit(t *testing.T) {
 
 			acc, err := testEnv.App.Accounts.GetAccountByID(ctx, tt.AccID)
 			require.NoError(t, err)
-			assert.Equal(t, tt.ExpectedAvailableCredit, acc.AvailableCredit)
+			assert.Equal(t, tt.ExpectedAvailableCredit, acc.MainBalance().AvailableCredit)
+		})
+	}
+}
+
+func Test_Transfer(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name                string
+		From                vos.AccountID
+		To                  vos.AccountID
+		Amount              vos.Money
+		Setup               func(t *testing.T) (vos.AccountID, vos.AccountID)
+		ExpectedError       error
+		ExpectedFromBalance vos.Money
+		ExpectedToBalance   vos.Money
+	}{
+		{
+			Name:          "expected invalid amount",
+			From:          "e031a99d-6191-4d02-8616-b5e3530caccb",
+			To:            "24dde2d4-5763-419d-9a93-3365ef55255c",
+			Amount:        brl(-10),
+			ExpectedError: accounts.ErrInvalidAmount,
+		},
+		{
+			Name:          "expected same account transfer",
+			From:          "e031a99d-6191-4d02-8616-b5e3530caccb",
+			To:            "e031a99d-6191-4d02-8616-b5e3530caccb",
+			Amount:        brl(10),
+			ExpectedError: accounts.ErrSameAccountTransfer,
+		},
+		{
+			Name: "expected destination account not found",
+			Setup: func(t *testing.T) (vos.AccountID, vos.AccountID) {
+				from, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+				require.NoError(t, err)
+
+				_, err = testEnv.App.Accounts.Deposit(ctx, "", from, brl(10))
+				require.NoError(t, err)
+
+				return from, "24dde2d4-5763-419d-9a93-3365ef55255c"
+			},
+			Amount:        brl(10),
+			ExpectedError: accounts.ErrAccountNotFound,
+		},
+		{
+			Name: "insufficient balance",
+			Setup: func(t *testing.T) (vos.AccountID, vos.AccountID) {
+				from, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+				require.NoError(t, err)
+
+				to, err := testEnv.App.Accounts.CreateAccount(ctx, "11144477735", brl(0))
+				require.NoError(t, err)
+
+				return from, to
+			},
+			Amount:        brl(10),
+			ExpectedError: accounts.ErrInsufficientBalance,
+		},
+		{
+			Name: "transfer happy path",
+			Setup: func(t *testing.T) (vos.AccountID, vos.AccountID) {
+				from, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+				require.NoError(t, err)
+
+				to, err := testEnv.App.Accounts.CreateAccount(ctx, "11144477735", brl(0))
+				require.NoError(t, err)
+
+				_, err = testEnv.App.Accounts.Deposit(ctx, "", from, brl(100))
+				require.NoError(t, err)
+
+				return from, to
+			},
+			Amount:              brl(30),
+			ExpectedFromBalance: brl(70),
+			ExpectedToBalance:   brl(30),
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			if tt.Setup != nil {
+				tt.From, tt.To = tt.Setup(t)
+			}
+
+			// test
+			entry, err := testEnv.GrpcFakeClient.Transfer(ctx, "", tt.From, tt.To, tt.Amount)
+
+			// assert
+			assert.ErrorIs(t, tt.ExpectedError, err)
+
+			if err != nil {
+				return
+			}
+
+			// responses carry the debited account amounts
+			assert.NotEmpty(t, entry.ID)
+			assert.Equal(t, tt.ExpectedFromBalance, entry.Balance)
+
+			from, err := testEnv.App.Accounts.GetAccountByID(ctx, tt.From)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedFromBalance, from.MainBalance().Balance)
+
+			to, err := testEnv.App.Accounts.GetAccountByID(ctx, tt.To)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedToBalance, to.MainBalance().Balance)
+		})
+	}
+}
+
+func Test_Transfer_Crossed(t *testing.T) {
+	ctx := context.Background()
+	defer truncatePostgresTables()
+
+	// prepare
+	accA, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+	require.NoError(t, err)
+	_, err = testEnv.App.Accounts.Deposit(ctx, "", accA, brl(1000))
+	require.NoError(t, err)
+
+	accB, err := testEnv.App.Accounts.CreateAccount(ctx, "11144477735", brl(0))
+	require.NoError(t, err)
+	_, err = testEnv.App.Accounts.Deposit(ctx, "", accB, brl(1000))
+	require.NoError(t, err)
+
+	// test
+	const transfers = 50
+	errs := make(chan error, 2*transfers)
+	var wg sync.WaitGroup
+	for i := 0; i < transfers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := testEnv.GrpcFakeClient.Transfer(ctx, "", accA, accB, brl(1))
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := testEnv.GrpcFakeClient.Transfer(ctx, "", accB, accA, brl(1))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// assert
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	a, err := testEnv.App.Accounts.GetAccountByID(ctx, accA)
+	require.NoError(t, err)
+	assert.Equal(t, brl(1000), a.MainBalance().Balance)
+
+	b, err := testEnv.App.Accounts.GetAccountByID(ctx, accB)
+	require.NoError(t, err)
+	assert.Equal(t, brl(1000), b.MainBalance().Balance)
+}
+
+func Test_NonActiveAccount(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name    string
+		Disable func(ctx context.Context, accID vos.AccountID) (entities.Account, error)
+		Request func(accID, other vos.AccountID) error
+	}{
+		{
+			Name:    "deposit on blocked account",
+			Disable: testEnv.App.Accounts.BlockAccount,
+			Request: func(accID, _ vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Deposit(ctx, "", accID, brl(10))
+				return err
+			},
+		},
+		{
+			Name:    "withdrawal on closed account",
+			Disable: testEnv.App.Accounts.CloseAccount,
+			Request: func(accID, _ vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Withdrawal(ctx, "", accID, brl(10))
+				return err
+			},
+		},
+		{
+			Name:    "credit reservation on blocked account",
+			Disable: testEnv.App.Accounts.BlockAccount,
+			Request: func(accID, _ vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.ReserveCreditLimit(ctx, "", accID, brl(10))
+				return err
+			},
+		},
+		{
+			Name:    "transfer to closed account",
+			Disable: testEnv.App.Accounts.CloseAccount,
+			Request: func(accID, other vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Transfer(ctx, "", other, accID, brl(10))
+				return err
+			},
+		},
+		{
+			Name:    "transfer from blocked account",
+			Disable: testEnv.App.Accounts.BlockAccount,
+			Request: func(accID, other vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Transfer(ctx, "", accID, other, brl(10))
+				return err
+			},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+			require.NoError(t, err)
+			_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(100))
+			require.NoError(t, err)
+
+			other, err := testEnv.App.Accounts.CreateAccount(ctx, "11144477735", brl(100))
+			require.NoError(t, err)
+			_, err = testEnv.App.Accounts.Deposit(ctx, "", other, brl(100))
+			require.NoError(t, err)
+
+			_, err = tt.Disable(ctx, accID)
+			require.NoError(t, err)
+
+			// test
+			err = tt.Request(accID, other)
+
+			// assert
+			assert.ErrorIs(t, err, accounts.ErrAccountNotActive)
+
+			acc, err := testEnv.App.Accounts.GetAccountByID(ctx, accID)
+			require.NoError(t, err)
+			assert.Equal(t, brl(100), acc.MainBalance().Balance)
+			assert.Equal(t, brl(100), acc.MainBalance().AvailableCredit)
 		})
 	}
 }
+
+func Test_WatchAccount(t *testing.T) {
+	testTable := []struct {
+		Name          string
+		AccID         vos.AccountID
+		Setup         func(ctx context.Context) (vos.AccountID, error)
+		Movements     func(t *testing.T, ctx context.Context, accID vos.AccountID)
+		ExpectedError error
+		Expected      []entities.BalanceUpdate
+	}{
+		{
+			Name:          "expected invalid acc id",
+			AccID:         "not-an-uuid",
+			ExpectedError: accounts.ErrInvalidAccID,
+		},
+		{
+			Name:          "expected account not found",
+			AccID:         "24dde2d4-5763-419d-9a93-3365ef55255c",
+			ExpectedError: accounts.ErrAccountNotFound,
+		},
+		{
+			Name: "balance updates are pushed after each successful movement",
+			Setup: func(ctx context.Context) (vos.AccountID, error) {
+				return testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+			},
+			Movements: func(t *testing.T, ctx context.Context, accID vos.AccountID) {
+				_, err := testEnv.GrpcFakeClient.Deposit(ctx, "", accID, brl(50))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.ReserveCreditLimit(ctx, "", accID, brl(30))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.Withdrawal(ctx, "", accID, brl(1000))
+				require.ErrorIs(t, err, accounts.ErrInsufficientBalance)
+				_, err = testEnv.GrpcFakeClient.Withdrawal(ctx, "", accID, brl(20))
+				require.NoError(t, err)
+			},
+			Expected: []entities.BalanceUpdate{
+				{Balance: brl(0), AvailableCredit: brl(100)},
+				{Operation: entities.OperationDeposit, Balance: brl(50), AvailableCredit: brl(100)},
+				{Operation: entities.OperationCreditReservation, Balance: brl(50), AvailableCredit: brl(70)},
+				{Operation: entities.OperationWithdrawal, Balance: brl(30), AvailableCredit: brl(70)},
+			},
+		},
+		{
+			Name: "both accounts of a transfer get their updates",
+			Setup: func(ctx context.Context) (vos.AccountID, error) {
+				return testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+			},
+			Movements: func(t *testing.T, ctx context.Context, accID vos.AccountID) {
+				other, err := testEnv.App.Accounts.CreateAccount(ctx, "11144477735", brl(0))
+				require.NoError(t, err)
+				_, err = testEnv.App.Accounts.Deposit(ctx, "", other, brl(50))
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.Transfer(ctx, "", other, accID, brl(20))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.Transfer(ctx, "", accID, other, brl(5))
+				require.NoError(t, err)
+			},
+			Expected: []entities.BalanceUpdate{
+				{Balance: brl(0), AvailableCredit: brl(0)},
+				{Operation: entities.OperationTransferIn, Balance: brl(20), AvailableCredit: brl(0)},
+				{Operation: entities.OperationTransferOut, Balance: brl(15), AvailableCredit: brl(0)},
+			},
+		},
+		{
+			Name: "hold changes are pushed along with the held amount",
+			Setup: func(ctx context.Context) (vos.AccountID, error) {
+				return testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+			},
+			Movements: func(t *testing.T, ctx context.Context, accID vos.AccountID) {
+				_, err := testEnv.GrpcFakeClient.Deposit(ctx, "", accID, brl(100))
+				require.NoError(t, err)
+
+				captured, err := testEnv.GrpcFakeClient.PlaceHold(ctx, "", accID, brl(30))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.CaptureHold(ctx, "", captured.ID, brl(10))
+				require.NoError(t, err)
+
+				voided, err := testEnv.GrpcFakeClient.PlaceHold(ctx, "", accID, brl(20))
+				require.NoError(t, err)
+				_, err = testEnv.GrpcFakeClient.VoidHold(ctx, voided.ID)
+				require.NoError(t, err)
+			},
+			Expected: []entities.BalanceUpdate{
+				{Balance: brl(0), AvailableCredit: brl(0), Held: brl(0)},
+				{Operation: entities.OperationDeposit, Balance: brl(100), AvailableCredit: brl(0), Held: brl(0)},
+				{HoldStatus: entities.HoldStatusActive, Balance: brl(100), AvailableCredit: brl(0), Held: brl(30)},
+				{Operation: entities.OperationWithdrawal, HoldStatus: entities.HoldStatusCaptured, Balance: brl(90), AvailableCredit: brl(0), Held: brl(0)},
+				{HoldStatus: entities.HoldStatusActive, Balance: brl(90), AvailableCredit: brl(0), Held: brl(20)},
+				{HoldStatus: entities.HoldStatusVoided, Balance: brl(90), AvailableCredit: brl(0), Held: brl(0)},
+			},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// prepare
+			if tt.Setup != nil {
+				accID, err := tt.Setup(ctx)
+				require.NoError(t, err)
+				tt.AccID = accID
+			}
+
+			// test
+			watcher, err := testEnv.GrpcFakeClient.WatchAccount(ctx, tt.AccID)
+			require.NoError(t, err)
+
+			first, err := watcher.Next()
+
+			// assert
+			assert.ErrorIs(t, err, tt.ExpectedError)
+			if err != nil {
+				return
+			}
+
+			// movements only start once the current balance was received, so none of them gets missed
+			tt.Movements(t, ctx, tt.AccID)
+
+			updates := []entities.BalanceUpdate{first}
+			for len(updates) < len(tt.Expected) {
+				update, err := watcher.Next()
+				require.NoError(t, err)
+				updates = append(updates, update)
+			}
+
+			for i, expected := range tt.Expected {
+				assert.Equal(t, tt.AccID, updates[i].AccountID)
+				assert.Equal(t, expected.Operation, updates[i].Operation)
+				assert.Equal(t, expected.Balance, updates[i].Balance)
+				assert.Equal(t, expected.AvailableCredit, updates[i].AvailableCredit)
+				assert.Equal(t, expected.Held.Int64(), updates[i].Held.Int64())
+				assert.Equal(t, expected.HoldStatus, updates[i].HoldStatus)
+				if expected.Operation != "" {
+					assert.NotEmpty(t, updates[i].EntryID)
+				}
+				if expected.HoldStatus != "" {
+					assert.NotEmpty(t, updates[i].HoldID)
+				}
+			}
+		})
+	}
+}
+
+func Test_CreateAccount_GRPC(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name          string
+		Doc           vos.Document
+		CreditLimit   vos.Money
+		Setup         func(t *testing.T)
+		ExpectedError error
+	}{
+		{
+			Name:          "expected invalid document",
+			Doc:           "12345678900",
+			ExpectedError: accounts.ErrInvalidDocument,
+		},
+		{
+			Name:          "expected invalid credit limit",
+			Doc:           "12345678909",
+			CreditLimit:   brl(-1),
+			ExpectedError: accounts.ErrInvalidCreditLimit,
+		},
+		{
+			Name: "expected account conflict",
+			Doc:  "123.456.789-09",
+			Setup: func(t *testing.T) {
+				_, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+				require.NoError(t, err)
+			},
+			ExpectedError: accounts.ErrAccountConflict,
+		},
+		{
+			Name:        "create account happy path",
+			Doc:         "123.456.789-09",
+			CreditLimit: brl(100),
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			if tt.Setup != nil {
+				tt.Setup(t)
+			}
+
+			// test
+			acc, err := testEnv.GrpcFakeClient.CreateAccount(ctx, tt.Doc, tt.CreditLimit)
+
+			// assert
+			assert.ErrorIs(t, err, tt.ExpectedError)
+			if err != nil {
+				return
+			}
+
+			assert.NotEmpty(t, acc.ID)
+			assert.Equal(t, vos.Document("12345678909"), acc.Document)
+			assert.Equal(t, vos.DocumentTypeCPF, acc.DocumentType)
+			assert.Equal(t, brl(0), acc.MainBalance().Balance)
+			assert.Equal(t, tt.CreditLimit, acc.MainBalance().CreditLimit)
+			assert.Equal(t, tt.CreditLimit, acc.MainBalance().AvailableCredit)
+			assert.Equal(t, entities.AccountStatusActive, acc.Status)
+			assert.False(t, acc.CreatedAt.IsZero())
+			assert.False(t, acc.UpdateAt.IsZero())
+		})
+	}
+}
+
+func Test_GetAccount_GRPC(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name          string
+		AccID         vos.AccountID
+		Setup         func() (vos.AccountID, error)
+		ExpectedError error
+	}{
+		{
+			Name:          "expected invalid acc id",
+			AccID:         "not-an-uuid",
+			ExpectedError: accounts.ErrInvalidAccID,
+		},
+		{
+			Name:          "expected account not found",
+			AccID:         "24dde2d4-5763-419d-9a93-3365ef55255c",
+			ExpectedError: accounts.ErrAccountNotFound,
+		},
+		{
+			Name: "get account happy path",
+			Setup: func() (vos.AccountID, error) {
+				accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(100))
+				if err != nil {
+					return "", err
+				}
+				_, err = testEnv.App.Accounts.Deposit(ctx, "", accID, brl(50))
+				return accID, err
+			},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			if tt.Setup != nil {
+				accID, err := tt.Setup()
+				require.NoError(t, err)
+				tt.AccID = accID
+			}
+
+			// test
+			acc, err := testEnv.GrpcFakeClient.GetAccount(ctx, tt.AccID)
+
+			// assert
+			assert.ErrorIs(t, err, tt.ExpectedError)
+			if err != nil {
+				return
+			}
+
+			expected, err := testEnv.App.Accounts.GetAccountByID(ctx, tt.AccID)
+			require.NoError(t, err)
+			assert.Equal(t, expected.ID, acc.ID)
+			assert.Equal(t, brl(50), acc.MainBalance().Balance)
+			assert.Equal(t, brl(100), acc.MainBalance().AvailableCredit)
+			assert.True(t, expected.CreatedAt.Equal(acc.CreatedAt))
+			assert.True(t, expected.UpdateAt.Equal(acc.UpdateAt))
+		})
+	}
+}
+
+func Test_GetAccountByDocument_GRPC(t *testing.T) {
+	ctx := context.Background()
+	testTable := []struct {
+		Name          string
+		Doc           vos.Document
+		ExpectedError error
+	}{
+		{
+			Name:          "expected invalid document",
+			Doc:           "123",
+			ExpectedError: accounts.ErrInvalidDocument,
+		},
+		{
+			Name:          "expected account not found",
+			Doc:           "11144477735",
+			ExpectedError: accounts.ErrAccountNotFound,
+		},
+		{
+			Name: "get account by raw document",
+			Doc:  "12345678909",
+		},
+		{
+			Name: "get account by formatted document",
+			Doc:  "123.456.789-09",
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+			require.NoError(t, err)
+
+			// test
+			acc, err := testEnv.GrpcFakeClient.GetAccountByDocument(ctx, tt.Doc)
+
+			// assert
+			assert.ErrorIs(t, err, tt.ExpectedError)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, accID, acc.ID)
+			assert.Equal(t, vos.Document("12345678909"), acc.Document)
+		})
+	}
+}
+
+func Test_ErrorDetails_GRPC(t *testing.T) {
+	testTable := []struct {
+		Name              string
+		Call              func(ctx context.Context, client acc_pb.AccountsServiceClient) error
+		ExpectedCode      codes.Code
+		ExpectedReason    string
+		ExpectedViolation string
+	}{
+		{
+			Name: "invalid amount points at the amount field",
+			Call: func(ctx context.Context, client acc_pb.AccountsServiceClient) error {
+				_, err := client.Deposit(ctx, &acc_pb.Request{AccountID: "55c217e7-177b-4289-afe3-d763c2ded6d9", Amount: -10})
+				return err
+			},
+			ExpectedCode:      codes.InvalidArgument,
+			ExpectedReason:    acc_grpc.ReasonInvalidAmount,
+			ExpectedViolation: "amount",
+		},
+		{
+			Name: "invalid account id points at the account id field",
+			Call: func(ctx context.Context, client acc_pb.AccountsServiceClient) error {
+				_, err := client.GetAccount(ctx, &acc_pb.GetAccountRequest{AccountID: "not-an-uuid"})
+				return err
+			},
+			ExpectedCode:      codes.InvalidArgument,
+			ExpectedReason:    acc_grpc.ReasonInvalidAccID,
+			ExpectedViolation: "accountID",
+		},
+		{
+			Name: "account not found",
+			Call: func(ctx context.Context, client acc_pb.AccountsServiceClient) error {
+				_, err := client.Deposit(ctx, &acc_pb.Request{AccountID: "55c217e7-177b-4289-afe3-d763c2ded6d9", Amount: 10})
+				return err
+			},
+			ExpectedCode:   codes.NotFound,
+			ExpectedReason: acc_grpc.ReasonAccountNotFound,
+		},
+	}
+
+	creds, err := clients.NewTLSCredentials("localhost", testEnv.Certs.CAFile, testEnv.Certs.ClientCertFile, testEnv.Certs.ClientKeyFile)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(testEnv.GrpcAddr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := acc_pb.NewAccountsServiceClient(conn)
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			// prepare
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+signTestToken(time.Hour, auth.ScopeAccounts))
+
+			// test
+			err := tt.Call(ctx, client)
+
+			// assert
+			st := status.Convert(err)
+			assert.Equal(t, tt.ExpectedCode, st.Code())
+
+			var info *errdetails.ErrorInfo
+			var badRequest *errdetails.BadRequest
+			var resp *acc_pb.Response
+			for _, detail := range st.Details() {
+				switch d := detail.(type) {
+				case *errdetails.ErrorInfo:
+					info = d
+				case *errdetails.BadRequest:
+					badRequest = d
+				case *acc_pb.Response:
+					resp = d
+				}
+			}
+
+			require.NotNil(t, info)
+			assert.Equal(t, acc_grpc.ErrorDomain, info.Domain)
+			assert.Equal(t, tt.ExpectedReason, info.Reason)
+
+			require.NotNil(t, resp)
+			assert.False(t, resp.Success)
+			assert.Equal(t, int32(tt.ExpectedCode), resp.ErrorCode)
+			assert.Equal(t, st.Message(), resp.ErrorDescription)
+			assert.Equal(t, tt.ExpectedReason, resp.ErrorReason)
+
+			if tt.ExpectedViolation == "" {
+				assert.Nil(t, badRequest)
+				return
+			}
+			require.NotNil(t, badRequest)
+			require.Len(t, badRequest.FieldViolations, 1)
+			assert.Equal(t, tt.ExpectedViolation, badRequest.FieldViolations[0].Field)
+		})
+	}
+}
+
+func Test_Response_GRPC(t *testing.T) {
+	defer truncatePostgresTables()
+	ctx := context.Background()
+
+	// prepare
+	accID, err := testEnv.App.Accounts.CreateAccount(ctx, "12345678909", brl(0))
+	require.NoError(t, err)
+
+	creds, err := clients.NewTLSCredentials("localhost", testEnv.Certs.CAFile, testEnv.Certs.ClientCertFile, testEnv.Certs.ClientKeyFile)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(testEnv.GrpcAddr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := acc_pb.NewAccountsServiceClient(conn)
+
+	// test
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+signTestToken(time.Hour, auth.ScopeAccounts))
+	resp, err := client.Deposit(ctx, &acc_pb.Request{AccountID: accID.String(), Amount: 10})
+
+	// assert
+	require.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Zero(t, resp.ErrorCode)
+	assert.Empty(t, resp.ErrorDescription)
+	assert.Empty(t, resp.ErrorReason)
+	assert.NotEmpty(t, resp.TransactionID)
+	assert.Equal(t, int64(10), resp.Balance)
+}
+
+func Test_Currencies_GRPC(t *testing.T) {
+	ctx := context.Background()
+	usd := func(amount int64) vos.Money { return vos.NewMoney(amount, vos.CurrencyUSD) }
+	testTable := []struct {
+		Name             string
+		Request          func(from, to vos.AccountID) (entities.Entry, error)
+		ExpectedError    error
+		ExpectedBalance  vos.Money
+		ExpectedBalances []vos.Money // of the first account, one per currency
+	}{
+		{
+			Name: "deposit in a secondary currency",
+			Request: func(from, to vos.AccountID) (entities.Entry, error) {
+				return testEnv.GrpcFakeClient.Deposit(ctx, "", from, usd(30))
+			},
+			ExpectedBalance:  usd(130),
+			ExpectedBalances: []vos.Money{brl(100), usd(130)},
+		},
+		{
+			Name: "withdrawal can't take from another currency",
+			Request: func(from, to vos.AccountID) (entities.Entry, error) {
+				return testEnv.GrpcFakeClient.Withdrawal(ctx, "", from, usd(150))
+			},
+			ExpectedError:    accounts.ErrInsufficientBalance,
+			ExpectedBalances: []vos.Money{brl(100), usd(100)},
+		},
+		{
+			Name: "currency not held by the account",
+			Request: func(from, to vos.AccountID) (entities.Entry, error) {
+				return testEnv.GrpcFakeClient.Deposit(ctx, "", from, vos.NewMoney(10, vos.CurrencyEUR))
+			},
+			ExpectedError:    accounts.ErrCurrencyMismatch,
+			ExpectedBalances: []vos.Money{brl(100), usd(100)},
+		},
+		{
+			Name: "unknown currency",
+			Request: func(from, to vos.AccountID) (entities.Entry, error) {
+				return testEnv.GrpcFakeClient.Deposit(ctx, "", from, vos.NewMoney(10, "XYZ"))
+			},
+			ExpectedError:    accounts.ErrInvalidCurrency,
+			ExpectedBalances: []vos.Money{brl(100), usd(100)},
+		},
+		{
+			Name: "transfer to an account not holding the currency",
+			Request: func(from, to vos.AccountID) (entities.Entry, error) {
+				return testEnv.GrpcFakeClient.Transfer(ctx, "", from, to, usd(10))
+			},
+			ExpectedError:    accounts.ErrCurrencyMismatch,
+			ExpectedBalances: []vos.Money{brl(100), usd(100)},
+		},
+		{
+			Name: "transfer in the main currency",
+			Request: func(from, to vos.AccountID) (entities.Entry, error) {
+				return testEnv.GrpcFakeClient.Transfer(ctx, "", from, to, brl(10))
+			},
+			ExpectedBalance:  brl(90),
+			ExpectedBalances: []vos.Money{brl(90), usd(100)},
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			from, err := testEnv.GrpcFakeClient.CreateAccount(ctx, "12345678909", brl(0), vos.CurrencyUSD)
+			require.NoError(t, err)
+			to, err := testEnv.GrpcFakeClient.CreateAccount(ctx, "11144477735", brl(0))
+			require.NoError(t, err)
+
+			_, err = testEnv.App.Accounts.Deposit(ctx, "", from.ID, brl(100))
+			require.NoError(t, err)
+			_, err = testEnv.App.Accounts.Deposit(ctx, "", from.ID, usd(100))
+			require.NoError(t, err)
+
+			// test
+			entry, err := tt.Request(from.ID, to.ID)
+
+			// assert
+			assert.ErrorIs(t, err, tt.ExpectedError)
+			if err == nil {
+				assert.Equal(t, tt.ExpectedBalance, entry.Balance)
+			}
+
+			acc, err := testEnv.GrpcFakeClient.GetAccount(ctx, from.ID)
+			require.NoError(t, err)
+			assert.Equal(t, vos.CurrencyBRL, acc.Currency)
+
+			balances := make([]vos.Money, 0, len(acc.Balances))
+			for _, balance := range acc.Balances {
+				balances = append(balances, balance.Balance)
+			}
+			assert.Equal(t, tt.ExpectedBalances, balances)
+		})
+	}
+}
+
+func Test_Holds(t *testing.T) {
+	ctx := context.Background()
+	placeHold := func(accID vos.AccountID, amount vos.Money) (entities.Hold, error) {
+		return testEnv.GrpcFakeClient.PlaceHold(ctx, "", accID, amount)
+	}
+	testTable := []struct {
+		Name                     string
+		Request                  func(t *testing.T, accID vos.AccountID) error
+		ExpectedError            error
+		ExpectedBalance          vos.Money
+		ExpectedAvailableBalance vos.Money
+	}{
+		{
+			Name: "place a hold",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				hold, err := placeHold(accID, brl(30))
+				if err == nil {
+					assert.Equal(t, entities.HoldStatusActive, hold.Status)
+					assert.Equal(t, accID, hold.AccountID)
+					assert.True(t, hold.ExpiresAt.After(time.Now()))
+				}
+				return err
+			},
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(70),
+		},
+		{
+			Name: "hold above the available balance",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				_, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				_, err = placeHold(accID, brl(80))
+				return err
+			},
+			ExpectedError:            accounts.ErrInsufficientBalance,
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(70),
+		},
+		{
+			Name: "invalid hold amount",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				_, err := placeHold(accID, brl(0))
+				return err
+			},
+			ExpectedError:            accounts.ErrInvalidAmount,
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(100),
+		},
+		{
+			Name: "withdrawal can't take held funds",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				_, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.Withdrawal(ctx, "", accID, brl(80))
+				return err
+			},
+			ExpectedError:            accounts.ErrInsufficientBalance,
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(70),
+		},
+		{
+			Name: "full capture",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				hold, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				entry, err := testEnv.GrpcFakeClient.CaptureHold(ctx, "", hold.ID, vos.Money{})
+				if err == nil {
+					assert.Equal(t, brl(70), entry.Balance)
+				}
+				return err
+			},
+			ExpectedBalance:          brl(70),
+			ExpectedAvailableBalance: brl(70),
+		},
+		{
+			Name: "partial capture releases the rest",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				hold, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.CaptureHold(ctx, "", hold.ID, brl(10))
+				if err != nil {
+					return err
+				}
+
+				captured, err := testEnv.AccRepo.GetHold(ctx, hold.ID)
+				require.NoError(t, err)
+				assert.Equal(t, entities.HoldStatusCaptured, captured.Status)
+				assert.Equal(t, brl(10), captured.CapturedAmount)
+				assert.NotEmpty(t, captured.EntryID)
+				return nil
+			},
+			ExpectedBalance:          brl(90),
+			ExpectedAvailableBalance: brl(90),
+		},
+		{
+			Name: "capture above the held amount",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				hold, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.CaptureHold(ctx, "", hold.ID, brl(40))
+				return err
+			},
+			ExpectedError:            accounts.ErrCaptureExceedsHold,
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(70),
+		},
+		{
+			Name: "capture twice",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				hold, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.CaptureHold(ctx, "", hold.ID, brl(10))
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.CaptureHold(ctx, "", hold.ID, brl(10))
+				return err
+			},
+			ExpectedError:            accounts.ErrHoldNotActive,
+			ExpectedBalance:          brl(90),
+			ExpectedAvailableBalance: brl(90),
+		},
+		{
+			Name: "idempotent capture",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				hold, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				first, err := testEnv.GrpcFakeClient.CaptureHold(ctx, "capture-key", hold.ID, brl(10))
+				require.NoError(t, err)
+
+				replayed, err := testEnv.GrpcFakeClient.CaptureHold(ctx, "capture-key", hold.ID, brl(10))
+				if err == nil {
+					assert.Equal(t, first.ID, replayed.ID)
+				}
+				return err
+			},
+			ExpectedBalance:          brl(90),
+			ExpectedAvailableBalance: brl(90),
+		},
+		{
+			Name: "capture reusing the key of a withdrawal",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.Withdrawal(ctx, "withdrawal-key", accID, brl(10))
+				require.NoError(t, err)
+
+				hold, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.CaptureHold(ctx, "withdrawal-key", hold.ID, brl(10))
+				return err
+			},
+			ExpectedError:            accounts.ErrIdempotencyKeyReused,
+			ExpectedBalance:          brl(90),
+			ExpectedAvailableBalance: brl(60),
+		},
+		{
+			Name: "void",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				hold, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				voided, err := testEnv.GrpcFakeClient.VoidHold(ctx, hold.ID)
+				if err == nil {
+					assert.Equal(t, entities.HoldStatusVoided, voided.Status)
+				}
+				return err
+			},
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(100),
+		},
+		{
+			Name: "capture after void",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				hold, err := placeHold(accID, brl(30))
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.VoidHold(ctx, hold.ID)
+				require.NoError(t, err)
+
+				_, err = testEnv.GrpcFakeClient.CaptureHold(ctx, "", hold.ID, vos.Money{})
+				return err
+			},
+			ExpectedError:            accounts.ErrHoldNotActive,
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(100),
+		},
+		{
+			Name: "hold not found",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				_, err := testEnv.GrpcFakeClient.VoidHold(ctx, "6a9f4d0e-1c1e-4b8e-9a55-0d8a3f1f6c21")
+				return err
+			},
+			ExpectedError:            accounts.ErrHoldNotFound,
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(100),
+		},
+		{
+			Name: "idempotent hold",
+			Request: func(t *testing.T, accID vos.AccountID) error {
+				first, err := testEnv.GrpcFakeClient.PlaceHold(ctx, "hold-key", accID, brl(30))
+				require.NoError(t, err)
+
+				replayed, err := testEnv.GrpcFakeClient.PlaceHold(ctx, "hold-key", accID, brl(30))
+				if err == nil {
+					assert.Equal(t, first.ID, replayed.ID)
+				}
+				return err
+			},
+			ExpectedBalance:          brl(100),
+			ExpectedAvailableBalance: brl(70),
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			defer truncatePostgresTables()
+
+			// prepare
+			acc, err := testEnv.GrpcFakeClient.CreateAccount(ctx, "12345678909", brl(0))
+			require.NoError(t, err)
+
+			_, err = testEnv.App.Accounts.Deposit(ctx, "", acc.ID, brl(100))
+			require.NoError(t, err)
+
+			// test
+			err = tt.Request(t, acc.ID)
+
+			// assert
+			assert.ErrorIs(t, err, tt.ExpectedError)
+
+			acc, err = testEnv.GrpcFakeClient.GetAccount(ctx, acc.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ExpectedBalance, acc.MainBalance().Balance)
+			assert.Equal(t, tt.ExpectedAvailableBalance, acc.MainBalance().AvailableBalance())
+		})
+	}
+}
+
+func Test_Holds_Expiry(t *testing.T) {
+	defer truncatePostgresTables()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// holds placed by this usecase expire right away, to be swept by the test env sweeper
+	usecase := accounts.NewUsecase(testEnv.AccRepo, events.NewBroker(), 50*time.Millisecond)
+
+	accID, err := usecase.CreateAccount(ctx, "12345678909", brl(0))
+	require.NoError(t, err)
+
+	_, err = usecase.Deposit(ctx, "", accID, brl(100))
+	require.NoError(t, err)
+
+	watcher, err := testEnv.GrpcFakeClient.WatchAccount(ctx, accID)
+	require.NoError(t, err)
+	_, err = watcher.Next()
+	require.NoError(t, err)
+
+	hold, err := usecase.PlaceHold(ctx, "", accID, brl(30))
+	require.NoError(t, err)
+
+	acc, err := testEnv.GrpcFakeClient.GetAccount(ctx, accID)
+	require.NoError(t, err)
+	assert.Equal(t, brl(70), acc.MainBalance().AvailableBalance())
+
+	assert.Eventually(t, func() bool {
+		expired, err := testEnv.AccRepo.GetHold(ctx, hold.ID)
+		return err == nil && expired.Status == entities.HoldStatusExpired
+	}, 5*time.Second, 20*time.Millisecond)
+
+	acc, err = testEnv.GrpcFakeClient.GetAccount(ctx, accID)
+	require.NoError(t, err)
+	assert.Equal(t, brl(100), acc.MainBalance().Balance)
+	assert.Equal(t, brl(100), acc.MainBalance().AvailableBalance())
+
+	// the hold got placed through a broker of its own, the first update watched being the one the sweeper publishes
+	update, err := watcher.Next()
+	require.NoError(t, err)
+	assert.Equal(t, hold.ID, update.HoldID)
+	assert.Equal(t, entities.HoldStatusExpired, update.HoldStatus)
+	assert.Equal(t, brl(100), update.AvailableBalance())
+
+	_, err = testEnv.GrpcFakeClient.CaptureHold(ctx, "", hold.ID, vos.Money{})
+	assert.ErrorIs(t, err, accounts.ErrHoldNotActive)
+
+	_, err = testEnv.GrpcFakeClient.VoidHold(ctx, hold.ID)
+	assert.ErrorIs(t, err, accounts.ErrHoldNotActive)
+}
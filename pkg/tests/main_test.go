@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/exec"
@@ -12,33 +13,50 @@ import (
 	"time"
 
 	"github.com/fernandodr19/mybank-acc/pkg/config"
+	"github.com/fernandodr19/mybank-acc/pkg/domain/vos"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/api"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/auth"
 	"github.com/fernandodr19/mybank-acc/pkg/gateway/db/postgres"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/events"
 	acc_grpc "github.com/fernandodr19/mybank-acc/pkg/gateway/grpc"
+	"github.com/fernandodr19/mybank-acc/pkg/gateway/jobs"
 	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/logger"
+	"github.com/fernandodr19/mybank-acc/pkg/instrumentation/tracing"
 	"github.com/fernandodr19/mybank-acc/pkg/tests/clients"
 	"github.com/sirupsen/logrus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"google.golang.org/grpc"
 
 	app "github.com/fernandodr19/mybank-acc/pkg"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/testcontainers/testcontainers-go"
 )
 
 type _testEnv struct {
 	// Server
-	Server *httptest.Server
+	Server     *httptest.Server
+	HTTPClient *http.Client
+	GrpcAddr   string
+	Certs      testCerts
 
 	// 3rd party fake Clients
 	GrpcFakeClient *clients.FakeClient
 
 	// DB
-	Conn    *pgx.Conn
+	Conn    *pgxpool.Pool
 	AccRepo *postgres.AccountsRepository
 
 	// App
 	App *app.App
+
+	// Tracing
+	Spans *tracetest.InMemoryExporter
+
+	// Events
+	Events *events.MemoryPublisher
 }
 
 var testEnv _testEnv
@@ -60,6 +78,10 @@ func setup() func() {
 		log.WithError(err).Fatal("failed loading config")
 	}
 
+	// Record spans in memory so that tests can assert on them
+	testEnv.Spans = tracetest.NewInMemoryExporter()
+	tracing.Register(sdktrace.NewTracerProvider(sdktrace.WithSyncer(testEnv.Spans)))
+
 	err = setupDockerTest()
 	if err != nil {
 		log.WithError(err).Fatal("failed setting up docker")
@@ -76,14 +98,63 @@ func setup() func() {
 	testEnv.Conn = dbConn
 	testEnv.AccRepo = postgres.NewAccountsRepository(dbConn)
 
-	app := app.BuildApp(dbConn)
+	app := app.BuildApp(dbConn, config.Holds{TTL: time.Hour})
 
 	testEnv.App = app
 
-	apiHandler := api.BuildHandler(app)
+	// Relay outbox events to memory
+	testEnv.Events = events.NewMemoryPublisher()
+	relay := events.NewRelay(postgres.NewOutboxRepository(dbConn), testEnv.Events, config.Outbox{
+		PollInterval: 20 * time.Millisecond,
+		BatchSize:    100,
+	})
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		relay.Run(relayCtx)
+		close(relayDone)
+	}()
+
+	// Expire holds in the background
+	sweeper := jobs.NewHoldsSweeper(app.Accounts, config.Holds{
+		SweepInterval: 20 * time.Millisecond,
+		BatchSize:     100,
+	})
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	sweeperDone := make(chan struct{})
+	go func() {
+		sweeper.Run(sweeperCtx)
+		close(sweeperDone)
+	}()
+
+	// Setup access token verification
+	cfg.Auth = config.Auth{HMACSecret: testAuthSecret}
+	verifier, err := auth.NewVerifier(cfg.Auth)
+	if err != nil {
+		log.WithError(err).Fatal("failed setting up auth")
+	}
+
+	apiHandler := api.BuildHandler(app, verifier)
+
+	// Setup mutual TLS for gRPC
+	certsDir, err := os.MkdirTemp("", "mybank-acc-certs")
+	if err != nil {
+		log.WithError(err).Fatal("failed creating certs dir")
+	}
+	certs, err := generateTestCerts(certsDir)
+	if err != nil {
+		log.WithError(err).Fatal("failed generating test certs")
+	}
+	testEnv.Certs = certs
+	cfg.GRPC.CertFile = certs.ServerCertFile
+	cfg.GRPC.KeyFile = certs.ServerKeyFile
+	cfg.GRPC.ClientCAFile = certs.CAFile
 
 	// gRPC server
-	grpcServer := acc_grpc.BuildHandler(app)
+	grpcServer, err := acc_grpc.BuildHandler(app, verifier, cfg.GRPC)
+	if err != nil {
+		log.WithError(err).Fatal("failed building gRPC handler")
+	}
 	go func() {
 		protocol, address := cfg.GRPC.Protocol, cfg.GRPC.Address()
 		l, err := net.Listen(protocol, address)
@@ -99,19 +170,73 @@ func setup() func() {
 	}()
 
 	// Fake gRPC client
-	clintGrpcConn, err := grpc.Dial(cfg.GRPC.Address(), grpc.WithInsecure())
+	testEnv.GrpcAddr = cfg.GRPC.Address()
+	grpcToken := clients.BearerToken(signTestToken(time.Hour, auth.ScopeAccounts))
+	grpcCreds, err := clients.NewTLSCredentials("localhost", certs.CAFile, certs.ClientCertFile, certs.ClientKeyFile)
+	if err != nil {
+		log.WithError(err).Fatalln("failed loading grpc client credentials")
+	}
+	clintGrpcConn, err := grpc.Dial(testEnv.GrpcAddr, grpc.WithTransportCredentials(grpcCreds), grpc.WithPerRPCCredentials(grpcToken))
 	if err != nil {
 		log.WithError(err).Fatalln("failed connecting grpc")
 	}
 	testEnv.GrpcFakeClient = clients.NewFakeAccountslient(clintGrpcConn)
 
 	testEnv.Server = httptest.NewServer(apiHandler)
+	testEnv.HTTPClient = &http.Client{
+		Transport: bearerTransport{
+			token: signTestToken(time.Hour, auth.ScopeAccounts, auth.ScopeAdmin),
+		},
+	}
 
 	return func() {
+		stopRelay()
+		<-relayDone
+		stopSweeper()
+		<-sweeperDone
 		clintGrpcConn.Close()
+		dbConn.Close()
+		os.RemoveAll(certsDir)
 	}
 }
 
+const (
+	testAuthSecret   = "test-secret"
+	testTokenSubject = "tests"
+)
+
+// signTestToken issues an access token accepted by the test servers
+func signTestToken(expiresIn time.Duration, scopes ...auth.Scope) string {
+	scope := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		scope = append(scope, string(s))
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+		Scope: strings.Join(scope, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   testTokenSubject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	}).SignedString([]byte(testAuthSecret))
+	if err != nil {
+		logger.Default().WithError(err).Fatalln("failed signing test token")
+	}
+
+	return token
+}
+
+// bearerTransport authenticates every request sent to the test server
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
 func setupDockerTest() error {
 	running, err := isDockerRunning([]string{
 		"pg-acc-test",
@@ -157,9 +282,9 @@ func isDockerRunning(expectedImages []string) (bool, error) {
 	return running, nil
 }
 
-func setupPostgresTest(cfg config.Postgres) (*pgx.Conn, error) {
+func setupPostgresTest(cfg config.Postgres) (*pgxpool.Pool, error) {
 	done := make(chan bool, 1)
-	var dbConn *pgx.Conn
+	var dbConn *pgxpool.Pool
 	var err error
 
 	// tries to connect within 5 seconds timeout
@@ -187,7 +312,17 @@ func setupPostgresTest(cfg config.Postgres) (*pgx.Conn, error) {
 func truncatePostgresTables() {
 	testEnv.Conn.Exec(context.Background(),
 		`TRUNCATE TABLE 
-			accounts
+			accounts,
+			balances,
+			holds,
+			entries,
+			credit_limit_changes,
+			outbox
 		CASCADE`,
 	)
 }
+
+// brl builds an amount in cents of brazilian reais, the default currency
+func brl(amount int64) vos.Money {
+	return vos.NewMoney(amount, vos.CurrencyBRL)
+}